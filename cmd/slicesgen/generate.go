@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	genericImportPath = "github.com/cheekybits/genny/generic"
+	header            = "// Code generated by slicesgen. DO NOT EDIT.\n\n"
+)
+
+var identPattern = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+
+// typeSet maps placeholder type names (ValueType, KeyType, ...) to specific types.
+type typeSet map[string]string
+
+// parseTypeSet parses arguments like "KeyType=string ValueType=int".
+func parseTypeSet(args []string) (typeSet, error) {
+	result := typeSet{}
+	for _, arg := range args {
+		for _, field := range strings.Fields(arg) {
+			pair := strings.SplitN(field, "=", 2)
+			if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+				return nil, fmt.Errorf("invalid type set: %s", field)
+			}
+			if strings.Contains(pair[1], ",") {
+				return nil, fmt.Errorf("only one specific type is allowed for %s: %s", pair[0], pair[1])
+			}
+			result[pair[0]] = pair[1]
+		}
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("type set is empty")
+	}
+	return result, nil
+}
+
+// placeholders returns placeholder names in the order they should be substituted.
+// Longer names come first so that a placeholder that contains another one is replaced safely.
+func (t typeSet) placeholders() []string {
+	names := make([]string, 0, len(t))
+	for name := range t {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// integerTypes is a set of built-in integer types that LSD radix sort accepts.
+var integerTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"uintptr": true, "byte": true, "rune": true,
+}
+
+// isStringRadix reports whether ValueType of types is sorted by radix sort for strings (true) or integers (false).
+// A named type like "type ID int" that is declared in Go files of dir is resolved to its underlying type.
+// It returns an error if ValueType is neither an integer type nor a string type.
+func isStringRadix(types typeSet, dir string) (bool, error) {
+	specific := types["ValueType"]
+	underlying, err := underlyingType(dir, specific)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case underlying == "string":
+		return true, nil
+	case integerTypes[underlying]:
+		return false, nil
+	}
+	return false, fmt.Errorf("radix sort requires integer type or string as ValueType: %q", specific)
+}
+
+// underlyingType follows type declarations in Go files of dir and returns the built-in type that name is defined by.
+// For example, it returns "int" for "ID" if dir has "type ID int". It returns name as is if it isn't declared in dir
+// or is defined by a composite type like a struct.
+func underlyingType(dir, name string) (string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if os.IsNotExist(err) {
+		return name, nil
+	} else if err != nil {
+		return "", err
+	}
+	declared := map[string]string{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok || genDecl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if ident, ok := typeSpec.Type.(*ast.Ident); ok {
+						declared[typeSpec.Name.Name] = ident.Name
+					}
+				}
+			}
+		}
+	}
+	// a chain can't be longer than the number of declarations, so an invalid cycle like "type A B; type B A" stops
+	for i := 0; i < len(declared); i++ {
+		next, ok := declared[name]
+		if !ok {
+			break
+		}
+		name = next
+	}
+	return name, nil
+}
+
+// templateFile is an additional template file that is merged into the main template (e.g. safe API).
+type templateFile struct {
+	name string
+	src  []byte
+}
+
+// generate expands a template source with types and returns gofmt'ed source code.
+// Declarations in extras are appended to the result.
+func generate(filename string, src []byte, pkgName string, types typeSet, extras ...templateFile) ([]byte, error) {
+	if len(extras) > 0 {
+		var err error
+		if src, err = mergeSources(filename, src, extras); err != nil {
+			return nil, err
+		}
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if pkgName != "" {
+		file.Name.Name = pkgName
+	}
+	if err := removeGenericDecls(file, types); err != nil {
+		return nil, err
+	}
+	removeImport(file, genericImportPath)
+
+	names := types.placeholders()
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.CallExpr:
+			// Conversion to pointer or composite types must be parenthesized: (*MyStruct)(v)
+			if ident, ok := node.Fun.(*ast.Ident); ok {
+				if specific, ok := types[ident.Name]; ok && !token.IsIdentifier(specific) {
+					ident.Name = "(" + specific + ")"
+				}
+			}
+		case *ast.Ident:
+			ident := node
+			ident.Name = substituteIdent(ident.Name, names, types)
+		}
+		return true
+	})
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			comment.Text = identPattern.ReplaceAllStringFunc(comment.Text, func(word string) string {
+				return substituteIdent(word, names, types)
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	if err := printer.Fprint(&buf, fset, file); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// mergeSources appends declarations in extras to src and adds their imports to the import declaration of src.
+// Sources are merged as text so that comments in extras are kept with their declarations.
+func mergeSources(filename string, src []byte, extras []templateFile) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[string]bool{}
+	for _, spec := range file.Imports {
+		existing[spec.Path.Value] = true
+	}
+	var imports, bodies bytes.Buffer
+	for _, extra := range extras {
+		extraFile, err := parser.ParseFile(fset, extra.name, extra.src, parser.ImportsOnly)
+		if err != nil {
+			return nil, err
+		}
+		bodyStart := extraFile.Name.End()
+		for _, decl := range extraFile.Decls {
+			bodyStart = decl.End()
+		}
+		for _, spec := range extraFile.Imports {
+			if !existing[spec.Path.Value] {
+				existing[spec.Path.Value] = true
+				fmt.Fprintf(&imports, "\t%s\n", spec.Path.Value)
+			}
+		}
+		bodies.WriteString("\n")
+		bodies.Write(extra.src[fset.Position(bodyStart).Offset:])
+	}
+	// insert imports before ")" of the first import declaration, or make a new one after package clause
+	insertAt := fset.Position(file.Name.End()).Offset
+	newImports := "\n\nimport (\n" + imports.String() + ")"
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT && genDecl.Rparen.IsValid() {
+			insertAt = fset.Position(genDecl.Rparen).Offset
+			newImports = imports.String()
+			break
+		}
+	}
+	if imports.Len() == 0 {
+		newImports = ""
+	}
+	var result bytes.Buffer
+	result.Write(src[:insertAt])
+	result.WriteString(newImports)
+	result.Write(src[insertAt:])
+	result.Write(bodies.Bytes())
+	return result.Bytes(), nil
+}
+
+// unstableDoc is the sentence of the Sort doc comment in sort.Slice templates. makeStable replaces it with stableDoc.
+const (
+	unstableDoc = "It uses sort.Slice, so it is not stable. slicesgen -stable generates it with sort.SliceStable."
+	stableDoc   = "It uses sort.SliceStable, so it is stable."
+)
+
+// makeStable replaces sort.Slice calls in src with sort.SliceStable so that the generated Sort is stable.
+// Calls are rewritten as text like mergeSources, and the doc comment that says Sort is not stable is fixed too.
+// It returns an error if src doesn't call sort.Slice.
+func makeStable(filename string, src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	var offsets []int
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "Slice" {
+			if pkg, ok := selector.X.(*ast.Ident); ok && pkg.Name == "sort" {
+				offsets = append(offsets, fset.Position(selector.Sel.End()).Offset)
+			}
+		}
+		return true
+	})
+	if len(offsets) == 0 {
+		return nil, fmt.Errorf("%s doesn't use sort.Slice (TimSort templates are always stable, pdqsort templates are never stable)", filename)
+	}
+	var result bytes.Buffer
+	last := 0
+	for _, offset := range offsets {
+		result.Write(src[last:offset])
+		result.WriteString("Stable")
+		last = offset
+	}
+	result.Write(src[last:])
+	return bytes.ReplaceAll(result.Bytes(), []byte(unstableDoc), []byte(stableDoc)), nil
+}
+
+// removeGenericDecls removes "type ValueType generic.Type" declarations.
+func removeGenericDecls(file *ast.File, types typeSet) error {
+	decls := file.Decls[:0]
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			decls = append(decls, decl)
+			continue
+		}
+		specs := genDecl.Specs[:0]
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if !isGenericType(typeSpec.Type) {
+				specs = append(specs, spec)
+				continue
+			}
+			if _, ok := types[typeSpec.Name.Name]; !ok {
+				return fmt.Errorf("specific type is not given for %s", typeSpec.Name.Name)
+			}
+			removeComment(file, typeSpec.Doc)
+			removeComment(file, typeSpec.Comment)
+		}
+		genDecl.Specs = specs
+		if len(specs) > 0 {
+			decls = append(decls, decl)
+		} else {
+			removeComment(file, genDecl.Doc)
+		}
+	}
+	file.Decls = decls
+	return nil
+}
+
+func isGenericType(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := selector.X.(*ast.Ident)
+	return ok && pkg.Name == "generic"
+}
+
+func removeComment(file *ast.File, group *ast.CommentGroup) {
+	if group == nil {
+		return
+	}
+	for i, g := range file.Comments {
+		if g == group {
+			file.Comments = append(file.Comments[:i], file.Comments[i+1:]...)
+			return
+		}
+	}
+}
+
+func removeImport(file *ast.File, path string) {
+	quoted := strconv.Quote(path)
+	decls := file.Decls[:0]
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			decls = append(decls, decl)
+			continue
+		}
+		specs := genDecl.Specs[:0]
+		for _, spec := range genDecl.Specs {
+			if spec.(*ast.ImportSpec).Path.Value != quoted {
+				specs = append(specs, spec)
+			}
+		}
+		genDecl.Specs = specs
+		if len(specs) > 0 {
+			decls = append(decls, decl)
+		}
+	}
+	file.Decls = decls
+	imports := file.Imports[:0]
+	for _, spec := range file.Imports {
+		if spec.Path.Value != quoted {
+			imports = append(imports, spec)
+		}
+	}
+	file.Imports = imports
+}
+
+// substituteIdent replaces placeholders in an identifier in the same manner as genny:
+//
+//	ValueType      -> *MyStruct
+//	ValueTypeSort  -> MyStructSort
+//	lessValueType  -> lessMyStruct
+func substituteIdent(name string, placeholders []string, types typeSet) string {
+	if specific, ok := types[name]; ok {
+		return specific
+	}
+	for _, placeholder := range placeholders {
+		if strings.Contains(name, placeholder) {
+			name = strings.Replace(name, placeholder, wordify(types[placeholder]), -1)
+		}
+	}
+	return name
+}
+
+// wordify turns a type into a capitalized word that can be used in identifiers.
+//
+//	*MyStruct -> MyStruct
+//	time.Time -> TimeTime
+//	[]byte    -> Bytes
+func wordify(s string) string {
+	var words []string
+	for _, word := range identPattern.FindAllString(s, -1) {
+		words = append(words, strings.ToUpper(word[:1])+word[1:])
+	}
+	result := strings.Join(words, "")
+	if strings.HasPrefix(s, "[]") {
+		result += "s"
+	}
+	return result
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/slices"
+)
+
+func TestWordify(t *testing.T) {
+	cases := map[string]string{
+		"int":       "Int",
+		"*MyStruct": "MyStruct",
+		"time.Time": "TimeTime",
+		"[]byte":    "Bytes",
+	}
+	for input, expected := range cases {
+		if actual := wordify(input); actual != expected {
+			t.Errorf("wordify(%q) = %q, want %q", input, actual, expected)
+		}
+	}
+}
+
+func TestParseTypeSet(t *testing.T) {
+	types, err := parseTypeSet([]string{"KeyType=string ValueType=*MyStruct"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if types["KeyType"] != "string" || types["ValueType"] != "*MyStruct" {
+		t.Errorf("unexpected type set: %v", types)
+	}
+	if _, err := parseTypeSet([]string{"ValueType=int,string"}); err == nil {
+		t.Error("multiple specific types should be an error")
+	}
+	if _, err := parseTypeSet([]string{"ValueType"}); err == nil {
+		t.Error("type set without specific type should be an error")
+	}
+}
+
+func TestGenerateBundledTemplates(t *testing.T) {
+	for _, name := range slices.TemplateNames {
+		src, err := slices.Template(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := generate(name+".go", src, "mypackage", typeSet{"ValueType": "*MyStruct", "KeyType": "int"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		code := string(result)
+		if strings.Contains(code, "ValueType") || strings.Contains(code, "KeyType") || strings.Contains(code, "generic") {
+			t.Errorf("%s: placeholder remains in generated code", name)
+		}
+		if !strings.Contains(code, "[]*MyStruct") {
+			t.Errorf("%s: specific type is not substituted", name)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), name+".go", result, 0); err != nil {
+			t.Errorf("%s: generated code is invalid: %v", name, err)
+		}
+	}
+}
+
+func TestGenerateMissingType(t *testing.T) {
+	src, err := slices.Template("standard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := generate("standard.go", src, "", typeSet{"KeyType": "int"}); err == nil {
+		t.Error("missing specific type should be an error")
+	}
+}
+
+func TestGenerateSafeTemplates(t *testing.T) {
+	for _, name := range slices.TemplateNames {
+		safeSrc, err := slices.SafeTemplate(name)
+		if err != nil {
+			continue
+		}
+		src, err := slices.Template(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := generate(name+".go", src, "mypackage", typeSet{"ValueType": "*MyStruct"}, templateFile{name: "safe.go", src: safeSrc})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), name+".go", result, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("%s: generated code is invalid: %v", name, err)
+		}
+		if len(file.Imports) == 0 || !strings.Contains(string(result), "func MyStructSafeRemoveAt(") {
+			t.Errorf("%s: safe API is not merged", name)
+		}
+		if !strings.Contains(string(result), "// MyStructSafeRemoveAt removes item") {
+			t.Errorf("%s: comments of safe API are lost", name)
+		}
+	}
+	if _, err := slices.SafeTemplate("map"); err == nil {
+		t.Error("map template doesn't have safe API")
+	}
+}
+
+func TestMergeSourcesAddsImports(t *testing.T) {
+	src := []byte("package a\n\nfunc A() {}\n")
+	extra := []byte("package a\n\nimport \"errors\"\n\n// B returns an error.\nfunc B() error { return errors.New(\"b\") }\n")
+	result, err := generate("a.go", src, "", typeSet{"ValueType": "int"}, templateFile{name: "b.go", src: extra})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "a.go", result, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated code is invalid: %v", err)
+	}
+	if len(file.Imports) != 1 || file.Imports[0].Path.Value != `"errors"` || !strings.Contains(string(result), "// B returns an error.\nfunc B() error") {
+		t.Errorf("unexpected merge result:\n%s", result)
+	}
+}
+
+func TestMakeStable(t *testing.T) {
+	for _, name := range slices.TemplateNames {
+		src, err := slices.Template(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stableSrc, err := makeStable(name+".go", src)
+		if !strings.Contains(string(src), "sort.Slice(") {
+			if err == nil {
+				t.Errorf("%s: template without sort.Slice should be an error", name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if strings.Contains(string(stableSrc), "sort.Slice(") || !strings.Contains(string(stableSrc), "sort.SliceStable(") {
+			t.Errorf("%s: sort.Slice is not replaced", name)
+		}
+		if strings.Contains(string(stableSrc), "not stable") {
+			t.Errorf("%s: doc comment still says that Sort is not stable", name)
+		}
+		if _, err := generate(name+".go", stableSrc, "mypackage", typeSet{"ValueType": "*MyStruct", "KeyType": "int"}); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestGenerateRadixTemplates(t *testing.T) {
+	for _, name := range []string{"comparable", "comparable-timsort", "comparable-pdqsort"} {
+		src, err := slices.Template(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, specific := range []string{"uint64", "string"} {
+			types := typeSet{"ValueType": specific}
+			stringType, err := isStringRadix(types, ".")
+			if err != nil {
+				t.Fatal(err)
+			}
+			radixSrc, err := slices.RadixTemplate(name, stringType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := generate(name+".go", src, "mypackage", types, templateFile{name: "radix.go", src: radixSrc})
+			if err != nil {
+				t.Fatalf("%s %s: %v", name, specific, err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), name+".go", result, 0); err != nil {
+				t.Fatalf("%s %s: generated code is invalid: %v", name, specific, err)
+			}
+			code := string(result)
+			if !strings.Contains(code, "func "+wordify(specific)+"RadixSort(") || strings.Contains(code, "go:build") {
+				t.Errorf("%s %s: radix sort is not merged", name, specific)
+			}
+		}
+	}
+	if _, err := slices.RadixTemplate("standard", false); err == nil {
+		t.Error("standard template doesn't have radix sort")
+	}
+	if _, err := isStringRadix(typeSet{"ValueType": "float64"}, "."); err == nil {
+		t.Error("radix sort of float64 should be an error")
+	}
+}
+
+func TestRadixNamedTypes(t *testing.T) {
+	dir := t.TempDir()
+	src := "package mypackage\n\ntype ID int32\n\ntype UserID ID\n\ntype Name string\n\ntype Score float64\n\ntype Point struct{ X, Y int }\n\ntype A B\n\ntype B A\n"
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for specific, expected := range map[string]bool{"ID": false, "UserID": false, "Name": true} {
+		stringType, err := isStringRadix(typeSet{"ValueType": specific}, dir)
+		if err != nil || stringType != expected {
+			t.Errorf("%s: stringType = %v, err = %v", specific, stringType, err)
+		}
+	}
+	for _, specific := range []string{"Score", "Point", "A", "Unknown"} {
+		if _, err := isStringRadix(typeSet{"ValueType": specific}, dir); err == nil {
+			t.Errorf("radix sort of %s should be an error", specific)
+		}
+	}
+	if _, err := isStringRadix(typeSet{"ValueType": "int"}, filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("output directory that doesn't exist yet should be accepted: %v", err)
+	}
+}
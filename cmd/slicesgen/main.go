@@ -0,0 +1,153 @@
+// slicesgen generates type specific sorted slice algorithms from the templates in this module.
+//
+// It is a replacement of genny for templates of github.com/shibukawa/slices:
+//
+//	slicesgen -template=timsort -out=mystructslices.go -pkg=mypackage gen "ValueType=*MyStruct"
+//
+// -template selects a bundled template (standard, timsort, comparable, comparable-timsort, pdqsort, ...).
+// -in reads a template file instead of bundled one. Without -out, it writes the result to stdout.
+// -safe appends safe API (functions that return errors instead of panics) of the template.
+// With -in, safe API is read from safe.go in the same directory as the template file.
+// -stable makes Sort of the sort.Slice based templates (standard, comparable, compare and key) stable
+// by using sort.SliceStable.
+// -radix appends RadixSort of the comparable templates: LSD radix sort for integer types and MSD radix sort for string.
+// ValueType can be a named type like "type ID int" that is declared in the package of the output file.
+// With -in, it is read from radix.go or radix_string.go in the same directory as the template file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shibukawa/slices"
+)
+
+const (
+	_ = iota
+	exitcodeInvalidArgs
+	exitcodeInvalidTypeSet
+	exitcodeSourceFileInvalid
+	exitcodeGenFailed
+	exitcodeDestFileFailed
+)
+
+func main() {
+	var (
+		in       = flag.String("in", "", "template file to parse instead of bundled template")
+		out      = flag.String("out", "", "file to save output to instead of stdout")
+		pkgName  = flag.String("pkg", "", "package name for generated files")
+		template = flag.String("template", "standard", "bundled template name: "+strings.Join(slices.TemplateNames, "|"))
+		safe     = flag.Bool("safe", false, "append safe API that returns errors instead of panics")
+		stable   = flag.Bool("stable", false, "use stable sort (sort.SliceStable) instead of sort.Slice")
+		radix    = flag.Bool("radix", false, "append radix sort for integer or string ValueType (comparable templates)")
+	)
+	flag.Usage = usage
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) < 2 || strings.ToLower(args[0]) != "gen" {
+		usage()
+		os.Exit(exitcodeInvalidArgs)
+	}
+
+	types, err := parseTypeSet(args[1:])
+	if err != nil {
+		fatal(exitcodeInvalidTypeSet, err)
+	}
+
+	var src []byte
+	filename := *in
+	if filename != "" {
+		src, err = ioutil.ReadFile(filename)
+	} else {
+		filename = *template + ".go"
+		src, err = slices.Template(*template)
+	}
+	if err != nil {
+		fatal(exitcodeSourceFileInvalid, err)
+	}
+
+	if *stable {
+		if src, err = makeStable(filename, src); err != nil {
+			fatal(exitcodeSourceFileInvalid, err)
+		}
+	}
+
+	var extras []templateFile
+	if *safe {
+		safeFilename := filepath.Join(filepath.Dir(filename), "safe.go")
+		var safeSrc []byte
+		if *in != "" {
+			safeSrc, err = ioutil.ReadFile(safeFilename)
+		} else {
+			safeFilename = *template + "-safe.go"
+			safeSrc, err = slices.SafeTemplate(*template)
+		}
+		if err != nil {
+			fatal(exitcodeSourceFileInvalid, err)
+		}
+		extras = append(extras, templateFile{name: safeFilename, src: safeSrc})
+	}
+	if *radix {
+		// named types in the package of the output file are resolved to their underlying types
+		outDir := "."
+		if *out != "" {
+			outDir = filepath.Dir(*out)
+		}
+		stringType, err := isStringRadix(types, outDir)
+		if err != nil {
+			fatal(exitcodeInvalidTypeSet, err)
+		}
+		radixFilename := filepath.Join(filepath.Dir(filename), slices.RadixTemplateFile(stringType))
+		var radixSrc []byte
+		if *in != "" {
+			radixSrc, err = ioutil.ReadFile(radixFilename)
+		} else {
+			radixFilename = *template + "-" + slices.RadixTemplateFile(stringType)
+			radixSrc, err = slices.RadixTemplate(*template, stringType)
+		}
+		if err != nil {
+			fatal(exitcodeSourceFileInvalid, err)
+		}
+		extras = append(extras, templateFile{name: radixFilename, src: radixSrc})
+	}
+
+	result, err := generate(filename, src, *pkgName, types, extras...)
+	if err != nil {
+		fatal(exitcodeGenFailed, err)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(result)
+	} else {
+		err = ioutil.WriteFile(*out, result, 0644)
+	}
+	if err != nil {
+		fatal(exitcodeDestFileFailed, err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: slicesgen [{flags}] gen "{types}"
+
+{flags}  - (optional) Command line flags (see below)
+{types}  - (required) Specific types for each generic type in the template
+{types} format:  {generic}={specific}[ {generic2}={specific2}]
+
+Examples:
+  ValueType=int
+  ValueType=*MyStruct
+  KeyType=string ValueType=int
+
+Flags:`)
+	flag.PrintDefaults()
+}
+
+func fatal(code int, err error) {
+	fmt.Fprintln(os.Stderr, "slicesgen:", err)
+	os.Exit(code)
+}
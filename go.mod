@@ -1,9 +1,8 @@
 module github.com/shibukawa/slices
 
-go 1.12
+go 1.21
 
 require (
 	github.com/cheekybits/genny v1.0.0
 	github.com/leanovate/gopter v0.2.4
-	golang.org/x/tools v0.0.0-20191001184121-329c8d646ebe // indirect
 )
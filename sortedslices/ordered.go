@@ -0,0 +1,174 @@
+package sortedslices
+
+import (
+	"cmp"
+	"sort"
+)
+
+// SortOrdered sorts an array in ascendant order.
+func SortOrdered[T cmp.Ordered](a []T) (err error) {
+	sort.Slice(a, func(i, j int) bool {
+		return a[i] < a[j]
+	})
+	return nil
+}
+
+// BinarySearchOrdered returns first index i that satisfies slices[i] <= item.
+func BinarySearchOrdered[T cmp.Ordered](sorted []T, item T) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)-1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if sorted[h] < item {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// IndexOfOrdered returns index of item. If item is not in a sorted slice, it returns -1.
+func IndexOfOrdered[T cmp.Ordered](sorted []T, item T) int {
+	if len(sorted) == 0 {
+		return -1
+	}
+	i := BinarySearchOrdered(sorted, item)
+	if sorted[i] == item {
+		return i
+	}
+	return -1
+}
+
+// ContainsOrdered returns true if item is in a sorted slice. Otherwise false.
+func ContainsOrdered[T cmp.Ordered](sorted []T, item T) bool {
+	return IndexOfOrdered(sorted, item) != -1
+}
+
+// InsertOrdered inserts item in correct position and returns a sorted slice.
+func InsertOrdered[T cmp.Ordered](sorted []T, item T) []T {
+	if len(sorted) == 0 {
+		return append(sorted, item)
+	}
+	i := BinarySearchOrdered(sorted, item)
+	if i == len(sorted)-1 && sorted[i] < item {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]T{item}, sorted[i:]...)...)
+}
+
+// RemoveOrdered removes item in a sorted slice.
+func RemoveOrdered[T cmp.Ordered](sorted []T, item T) []T {
+	i := IndexOfOrdered(sorted, item)
+	if i != -1 {
+		return RemoveAt(sorted, i)
+	}
+	return sorted
+}
+
+// IterateOverOrdered iterates over input sorted slices and calls callback with each items in ascendant order.
+func IterateOverOrdered[T cmp.Ordered](callback func(item T, srcIndex int), sorted ...[]T) {
+	sourceSlices := make([][]T, 0, len(sorted))
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
+		if len(src) > 0 {
+			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
+		}
+	}
+	sourceSliceCount := len(sourceSlices)
+	if sourceSliceCount == 0 {
+		return
+	}
+	indexes := make([]int, sourceSliceCount)
+	for len(sourceSlices) > 1 {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < len(sourceSlices); i++ {
+			if sourceSlices[i][indexes[i]] < minItem {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		callback(minItem, sliceIndex[minSlice])
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// UnionOrdered unions sorted slices and returns new slices.
+func UnionOrdered[T cmp.Ordered](sorted ...[]T) []T {
+	length := 0
+	for _, src := range sorted {
+		length += len(src)
+	}
+	if length == 0 {
+		return nil
+	}
+	result := make([]T, 0, length)
+	IterateOverOrdered(func(item T, srcIndex int) {
+		result = append(result, item)
+	}, sorted...)
+	return result
+}
+
+// DifferenceOrdered creates difference group of sorted slices and returns.
+func DifferenceOrdered[T cmp.Ordered](sorted1, sorted2 []T) []T {
+	var result []T
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		if sorted1[i] < sorted2[j] {
+			result = append(result, sorted1[i])
+			i++
+		} else if sorted2[j] < sorted1[i] {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// IntersectionOrdered creates intersection group of sorted slices and returns.
+//
+// It doesn't reorder the sorted argument.
+func IntersectionOrdered[T cmp.Ordered](sorted ...[]T) []T {
+	if len(sorted) == 0 {
+		return nil
+	}
+	sources := sortByLength(sorted)
+	var result []T
+	cursors := make([]int, len(sources))
+	for _, value := range sources[0] {
+		found := true
+		for i := 1; i < len(sources); i++ {
+			src := sources[i]
+			for cursors[i] < len(src) && src[cursors[i]] < value {
+				cursors[i]++
+			}
+			if cursors[i] == len(src) {
+				return result
+			}
+			if value < src[cursors[i]] {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,285 @@
+package sortedslices
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestSortOrderedInt(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("sort returns stable", prop.ForAll(func(input []int) bool {
+		timSort := make([]int, len(input))
+		defaultSort := make([]int, len(input))
+		copy(timSort, input)
+		copy(defaultSort, input)
+
+		SortOrdered(timSort)
+		sort.Ints(defaultSort)
+		return deepEqual(timSort, defaultSort)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestBinarySearchOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("binary search found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		orig := make([]int, len(input))
+		copy(orig, input)
+		SortOrdered(input)
+		i := BinarySearchOrdered(input, value)
+		if input[i] != value {
+			t.Log(i, value, input[i])
+			t.Log(orig)
+			t.Log(input)
+		}
+		return input[i] == value
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIndexOfOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("indexOf found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		SortOrdered(input)
+		i := IndexOfOrdered(input, value)
+		return i != -1 && input[i] == value
+	}, numSliceGenerator))
+
+	properties.Property("indexOf returns -1 if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		array := input[1:]
+		SortOrdered(array)
+		i := IndexOfOrdered(array, value)
+		return i == -1
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestContainsOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("contains returns true if item found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		SortOrdered(input)
+		return ContainsOrdered(input, value)
+	}, numSliceGenerator))
+
+	properties.Property("indexOf returns false if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		array := input[1:]
+		SortOrdered(array)
+		return !ContainsOrdered(array, value)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestInsertOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("insert returns new sorted slices", prop.ForAll(func(input []int) bool {
+		expected := make([]int, len(input))
+		copy(expected, input)
+		SortOrdered(expected)
+
+		value := input[0]
+		array := input[1:]
+		SortOrdered(array)
+
+		inserted := InsertOrdered(array, value)
+
+		return reflect.DeepEqual(expected, inserted)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRemoveOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("remove removes item of array", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		SortOrdered(input)
+
+		removedArray := RemoveOrdered(input, value)
+
+		return len(removedArray) == len(input)-1 && !ContainsOrdered(removedArray, value)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestUnionOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("union item of slices", prop.ForAll(func(input1, input2, input3 []int) bool {
+		SortOrdered(input1)
+		SortOrdered(input2)
+		SortOrdered(input3)
+
+		union := UnionOrdered(input1, input2, input3)
+
+		if len(union) == 0 {
+			return true
+		}
+
+		expected := make([]int, len(union))
+		copy(expected, union)
+		SortOrdered(expected)
+
+		return reflect.DeepEqual(expected, union)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestDifferenceOrdered(t *testing.T) {
+	result := DifferenceOrdered([]int{10, 20, 30, 40}, []int{20, 30})
+	if len(result) != 2 {
+		t.Error("length should be 2")
+	}
+}
+
+func TestIntersectionOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("intersection item of slices", prop.ForAll(func(src1, src2, common []int) bool {
+		SortOrdered(src1)
+		SortOrdered(src2)
+		SortOrdered(common)
+
+		src1 = DifferenceOrdered(src1, src2)
+		common = DifferenceOrdered(common, src2)
+
+		input1 := UnionOrdered(src1, common)
+		input2 := UnionOrdered(src2, common)
+
+		actual := IntersectionOrdered(input1, input2)
+		if len(actual) != len(common) {
+			return false
+		}
+		return deepEqual(common, actual)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIterateOverOrdered(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("iterate item of slices", prop.ForAll(func(input1, input2, input3 []int) bool {
+		SortOrdered(input1)
+		SortOrdered(input2)
+		SortOrdered(input3)
+
+		var result []int
+		IterateOverOrdered(func(item, srcIndex int) {
+			result = append(result, item)
+		}, input1, input2, input3)
+
+		if len(result) == 0 {
+			return true
+		}
+
+		expected := make([]int, len(result))
+		copy(expected, result)
+		SortOrdered(expected)
+
+		return reflect.DeepEqual(expected, result)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func benchmarkContainsOrdered(b *testing.B, count int) {
+	slice := make([]int, count)
+	for i := 0; i < count; i++ {
+		slice[i] = rand.Int()
+	}
+	value := slice[0]
+	SortOrdered(slice)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = ContainsOrdered(slice, value)
+	}
+}
+
+func BenchmarkContainsOrdered100(b *testing.B) {
+	benchmarkContainsOrdered(b, 20)
+}
+
+func benchmarkMapContains(b *testing.B, count int) {
+	m := make(map[int]bool, count)
+	var value int
+	for i := 0; i < count; i++ {
+		v := rand.Int()
+		if i == 0 {
+			value = v
+		}
+		m[v] = true
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m[value]
+	}
+}
+
+func BenchmarkMapContains100(b *testing.B) {
+	benchmarkMapContains(b, 20)
+}
+
+func TestEmptySliceOrdered(t *testing.T) {
+	if IndexOfOrdered(nil, 10) != -1 {
+		t.Error("indexOf should return -1 for empty slice")
+	}
+	if ContainsOrdered([]string{}, "a") {
+		t.Error("contains should return false for empty slice")
+	}
+	if !reflect.DeepEqual(InsertOrdered(nil, 1.5), []float64{1.5}) {
+		t.Error("insert should return slice that has only inserted item")
+	}
+	if IntersectionOrdered[int]() != nil {
+		t.Error("intersection without slices should return nil")
+	}
+}
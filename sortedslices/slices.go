@@ -0,0 +1,210 @@
+// Package sortedslices provides algorithms for sorted slices with type parameters.
+//
+// It has the same functions that the templates of github.com/shibukawa/slices generate,
+// so it can be used without code generation step:
+//
+//	Sort(slices []T, lt LessThan[T]) error
+//	BinarySearch(sorted []T, item T, lt LessThan[T]) int
+//	IndexOf(sorted []T, item T, lt LessThan[T]) int
+//	Contains(sorted []T, item T, lt LessThan[T]) bool
+//	Insert(sorted []T, item T, lt LessThan[T]) []T
+//	Remove(sorted []T, item T, lt LessThan[T]) []T
+//	RemoveAt(sorted []T, i int) []T
+//	IterateOver(lt LessThan[T], callback func(item T, srcIndex int), sorted ...[]T)
+//	Union(lt LessThan[T], sorted ...[]T) []T
+//	Difference(lt LessThan[T], sorted1, sorted2 []T) []T
+//	Intersection(lt LessThan[T], sorted ...[]T) []T
+//
+// Functions that have "Ordered" suffix (SortOrdered, BinarySearchOrdered, ...) are the same as
+// the comparable templates. They use "<" operator as a comparator.
+package sortedslices
+
+import (
+	"sort"
+)
+
+// LessThan is Delegate type that sorting uses as a comparator
+type LessThan[T any] func(a, b T) bool
+
+// Sort sorts an array using the provided comparator
+func Sort[T any](a []T, lt LessThan[T]) (err error) {
+	sort.Slice(a, func(i, j int) bool {
+		return lt(a[i], a[j])
+	})
+	return nil
+}
+
+// BinarySearch returns first index i that satisfies slices[i] <= item.
+func BinarySearch[T any](sorted []T, item T, lt LessThan[T]) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)-1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if lt(sorted[h], item) {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// IndexOf returns index of item. If item is not in a sorted slice, it returns -1.
+func IndexOf[T any](sorted []T, item T, lt LessThan[T]) int {
+	if len(sorted) == 0 {
+		return -1
+	}
+	i := BinarySearch(sorted, item, lt)
+	if !lt(sorted[i], item) && !lt(item, sorted[i]) {
+		return i
+	}
+	return -1
+}
+
+// Contains returns true if item is in a sorted slice. Otherwise false.
+func Contains[T any](sorted []T, item T, lt LessThan[T]) bool {
+	return IndexOf(sorted, item, lt) != -1
+}
+
+// Insert inserts item in correct position and returns a sorted slice.
+func Insert[T any](sorted []T, item T, lt LessThan[T]) []T {
+	if len(sorted) == 0 {
+		return append(sorted, item)
+	}
+	i := BinarySearch(sorted, item, lt)
+	if i == len(sorted)-1 && lt(sorted[i], item) {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]T{item}, sorted[i:]...)...)
+}
+
+// Remove removes item in a sorted slice.
+func Remove[T any](sorted []T, item T, lt LessThan[T]) []T {
+	i := IndexOf(sorted, item, lt)
+	if i != -1 {
+		return RemoveAt(sorted, i)
+	}
+	return sorted
+}
+
+// RemoveAt removes item in a slice.
+func RemoveAt[T any](sorted []T, i int) []T {
+	return append(sorted[:i], sorted[i+1:]...)
+}
+
+// IterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+func IterateOver[T any](lt LessThan[T], callback func(item T, srcIndex int), sorted ...[]T) {
+	sourceSlices := make([][]T, 0, len(sorted))
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
+		if len(src) > 0 {
+			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
+		}
+	}
+	sourceSliceCount := len(sourceSlices)
+	if sourceSliceCount == 0 {
+		return
+	}
+	indexes := make([]int, sourceSliceCount)
+	for len(sourceSlices) > 1 {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < len(sourceSlices); i++ {
+			if lt(sourceSlices[i][indexes[i]], minItem) {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		callback(minItem, sliceIndex[minSlice])
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// Union unions sorted slices and returns new slices.
+func Union[T any](lt LessThan[T], sorted ...[]T) []T {
+	length := 0
+	for _, src := range sorted {
+		length += len(src)
+	}
+	if length == 0 {
+		return nil
+	}
+	result := make([]T, 0, length)
+	IterateOver(lt, func(item T, srcIndex int) {
+		result = append(result, item)
+	}, sorted...)
+	return result
+}
+
+// Difference creates difference group of sorted slices and returns.
+func Difference[T any](lt LessThan[T], sorted1, sorted2 []T) []T {
+	var result []T
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		if lt(sorted1[i], sorted2[j]) {
+			result = append(result, sorted1[i])
+			i++
+		} else if lt(sorted2[j], sorted1[i]) {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// Intersection creates intersection group of sorted slices and returns.
+//
+// It doesn't reorder the sorted argument.
+func Intersection[T any](lt LessThan[T], sorted ...[]T) []T {
+	if len(sorted) == 0 {
+		return nil
+	}
+	sources := sortByLength(sorted)
+	var result []T
+	cursors := make([]int, len(sources))
+	for _, value := range sources[0] {
+		found := true
+		for i := 1; i < len(sources); i++ {
+			src := sources[i]
+			for cursors[i] < len(src) && lt(src[cursors[i]], value) {
+				cursors[i]++
+			}
+			if cursors[i] == len(src) {
+				return result
+			}
+			if lt(value, src[cursors[i]]) {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// sortByLength returns copy of sorted that is ordered by length of each slice.
+func sortByLength[T any](sorted [][]T) [][]T {
+	sources := make([][]T, len(sorted))
+	copy(sources, sorted)
+	sort.SliceStable(sources, func(i, j int) bool {
+		return len(sources[i]) < len(sources[j])
+	})
+	return sources
+}
@@ -0,0 +1,287 @@
+package sortedslices
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func deepEqual(v1, v2 []int) bool {
+	if len(v1) == 0 && len(v2) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+func lessThan(a, b int) bool {
+	return a < b
+}
+
+func TestSortInt(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("sort returns stable", prop.ForAll(func(input []int) bool {
+		timSort := make([]int, len(input))
+		defaultSort := make([]int, len(input))
+		copy(timSort, input)
+		copy(defaultSort, input)
+
+		Sort(timSort, lessThan)
+		sort.Ints(defaultSort)
+		return reflect.DeepEqual(timSort, defaultSort)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestBinarySearch(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("binary search found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		orig := make([]int, len(input))
+		copy(orig, input)
+		Sort(input, lessThan)
+		i := BinarySearch(input, value, lessThan)
+		if input[i] != value {
+			t.Log(i, value, input[i])
+			t.Log(orig)
+			t.Log(input)
+		}
+		return input[i] == value
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIndexOf(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("indexOf found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		Sort(input, lessThan)
+		i := IndexOf(input, value, lessThan)
+		return i != -1 && input[i] == value
+	}, numSliceGenerator))
+
+	properties.Property("indexOf returns -1 if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		array := input[1:]
+		Sort(array, lessThan)
+		i := IndexOf(array, value, lessThan)
+		return i == -1
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestContains(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("contains returns true if item found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		Sort(input, lessThan)
+		return Contains(input, value, lessThan)
+	}, numSliceGenerator))
+
+	properties.Property("indexOf returns false if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		array := input[1:]
+		Sort(array, lessThan)
+		return !Contains(array, value, lessThan)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestInsert(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("insert returns new sorted slices", prop.ForAll(func(input []int) bool {
+		expected := make([]int, len(input))
+		copy(expected, input)
+		Sort(expected, lessThan)
+
+		value := input[0]
+		array := input[1:]
+		Sort(array, lessThan)
+
+		inserted := Insert(array, value, lessThan)
+
+		return reflect.DeepEqual(expected, inserted)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRemove(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("remove removes item of array", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		Sort(input, lessThan)
+
+		removedArray := Remove(input, value, lessThan)
+
+		return len(removedArray) == len(input)-1 && !Contains(removedArray, value, lessThan)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestUnion(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("union item of slices", prop.ForAll(func(input1, input2, input3 []int) bool {
+		Sort(input1, lessThan)
+		Sort(input2, lessThan)
+		Sort(input3, lessThan)
+
+		union := Union(lessThan, input1, input2, input3)
+
+		if len(union) == 0 {
+			return true
+		}
+
+		expected := make([]int, len(union))
+		copy(expected, union)
+		Sort(expected, lessThan)
+
+		return reflect.DeepEqual(expected, union)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestUnionRegression(t *testing.T) {
+	input1 := []int{}
+	input2 := []int{}
+	input3 := []int{-969021752, -217052717, -131317604, 1466473779, 1779713542}
+	merged := Union(lessThan, input1, input2, input3)
+	t.Log(merged)
+}
+
+func TestDifference(t *testing.T) {
+	result := Difference(lessThan, []int{10, 20, 30, 40}, []int{20, 30})
+	if len(result) != 2 {
+		t.Error("length should be 2")
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("intersection item of slices", prop.ForAll(func(src1, src2, common []int) bool {
+		Sort(src1, lessThan)
+		Sort(src2, lessThan)
+		Sort(common, lessThan)
+
+		src1 = Difference(lessThan, src1, src2)
+		common = Difference(lessThan, common, src2)
+
+		input1 := Union(lessThan, src1, common)
+		input2 := Union(lessThan, src2, common)
+
+		actual := Intersection(lessThan, input1, input2)
+		if len(actual) != len(common) {
+			return false
+		}
+		return deepEqual(common, actual)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIterateOver(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("iterate item of slices", prop.ForAll(func(input1, input2, input3 []int) bool {
+		Sort(input1, lessThan)
+		Sort(input2, lessThan)
+		Sort(input3, lessThan)
+
+		var result []int
+		IterateOver(lessThan, func(item, srcIndex int) {
+			result = append(result, item)
+		}, input1, input2, input3)
+
+		if len(result) == 0 {
+			return true
+		}
+
+		expected := make([]int, len(result))
+		copy(expected, result)
+		Sort(expected, lessThan)
+
+		return reflect.DeepEqual(expected, result)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestEmptySlice(t *testing.T) {
+	if IndexOf(nil, 10, lessThan) != -1 {
+		t.Error("indexOf should return -1 for empty slice")
+	}
+	if Contains(nil, 10, lessThan) {
+		t.Error("contains should return false for empty slice")
+	}
+	if len(Remove(nil, 10, lessThan)) != 0 {
+		t.Error("remove should return empty slice")
+	}
+	if !reflect.DeepEqual(Insert(nil, 10, lessThan), []int{10}) {
+		t.Error("insert should return slice that has only inserted item")
+	}
+	if Intersection(lessThan) != nil {
+		t.Error("intersection without slices should return nil")
+	}
+}
+
+func TestIntersectionKeepsArguments(t *testing.T) {
+	input := [][]int{{1, 2, 3, 4}, {2, 3}, {3}}
+	Intersection(lessThan, input...)
+	if len(input[0]) != 4 || len(input[1]) != 2 || len(input[2]) != 1 {
+		t.Error("intersection should not reorder arguments")
+	}
+}
+
+func TestIterateOverSourceIndex(t *testing.T) {
+	var sources []int
+	IterateOver(lessThan, func(item, srcIndex int) {
+		sources = append(sources, srcIndex)
+	}, []int{}, []int{1, 4}, []int{2, 3})
+	if !reflect.DeepEqual(sources, []int{1, 2, 2, 1}) {
+		t.Errorf("srcIndex should be index of arguments: %v", sources)
+	}
+}
@@ -0,0 +1,69 @@
+//go:build ignore
+
+package template_comparable_timsort
+
+// Radix sort: these functions are generated by slicesgen -radix when ValueType is string.
+// This file is excluded from the build because ValueType of the template is not a string.
+
+// radixSortMinLengthValueType is the minimum length of a bucket that ValueTypeRadixSort sorts with radix sort.
+// Shorter buckets are sorted by ValueTypeSort.
+const radixSortMinLengthValueType = 64
+
+// ValueTypeRadixSort sorts an array of strings in ascendant order with MSD radix sort.
+// It distributes items into buckets by a byte at the same position and sorts each bucket by the next byte,
+// so it never compares the common prefix of items twice. It allocates a buffer as large as the array.
+func ValueTypeRadixSort(a []ValueType) (err error) {
+	if len(a) < radixSortMinLengthValueType {
+		return ValueTypeSort(a)
+	}
+	msdRadixSortValueType(a, make([]ValueType, len(a)), 0)
+	return nil
+}
+
+// msdRadixSortValueType sorts a whose items have the same prefix of length depth. buf is a buffer as large as a.
+func msdRadixSortValueType(a, buf []ValueType, depth int) {
+	for {
+		if len(a) < radixSortMinLengthValueType {
+			ValueTypeSort(a)
+			return
+		}
+		// bucket 0 is for items that end at depth. It comes first because it is a prefix of other items.
+		var offsets [257]int
+		for _, s := range a {
+			offsets[radixByteValueType(s, depth)]++
+		}
+		if offsets[0] == len(a) {
+			return
+		}
+		if offsets[radixByteValueType(a[0], depth)] == len(a) {
+			// all items have the same byte at depth
+			depth++
+			continue
+		}
+		var starts [258]int
+		for i, count := range offsets {
+			starts[i+1] = starts[i] + count
+		}
+		copy(offsets[:], starts[:257])
+		for _, s := range a {
+			b := radixByteValueType(s, depth)
+			buf[offsets[b]] = s
+			offsets[b]++
+		}
+		copy(a, buf)
+		for b := 1; b < 257; b++ {
+			if lo, hi := starts[b], starts[b+1]; hi-lo > 1 {
+				msdRadixSortValueType(a[lo:hi], buf[lo:hi], depth+1)
+			}
+		}
+		return
+	}
+}
+
+// radixByteValueType returns the byte of s at depth plus one, or 0 if s is shorter than depth+1.
+func radixByteValueType(s ValueType, depth int) int {
+	if depth < len(s) {
+		return int(s[depth]) + 1
+	}
+	return 0
+}
@@ -4,10 +4,21 @@ import (
 	"errors"
 	"fmt"
 	"github.com/cheekybits/genny/generic"
+	"runtime"
+	"sort"
+	"sync"
 )
 
 type ValueType generic.Number
 
+// ErrValueTypeComparatorContract is returned when items can't be ordered by < (for example, an item is NaN
+// that is not equal to itself or the sort result is not in order). Use errors.Is to check it.
+var ErrValueTypeComparatorContract = errors.New("comparison method violates its general contract")
+
+// ErrValueTypeInternalInvariant is returned when TimSort detects a broken internal invariant in the middle of sort.
+// It is usually caused by a comparator that returns inconsistent results. Use errors.Is to check it.
+var ErrValueTypeInternalInvariant = errors.New("internal invariant of sort is broken")
+
 // Package timsort provides fast stable sort, uses external comparator.
 //
 // A stable, adaptive, iterative mergesort that requires far fewer than
@@ -98,13 +109,12 @@ type timSortHandler struct {
 }
 
 /**
- * Creates a TimSort instance to maintain the state of an ongoing sort.
+ * Prepares the TimSort instance to maintain the state of an ongoing sort.
+ * Buffers of the previous sort are reused if they are large enough.
  *
  * @param a the array to be sorted
  */
-func newTimSort(a []ValueType) (h *timSortHandler) {
-	h = new(timSortHandler)
-
+func (h *timSortHandler) init(a []ValueType) {
 	h.a = a
 	h.minGallop = minGallop
 	h.stackSize = 0
@@ -117,7 +127,9 @@ func newTimSort(a []ValueType) (h *timSortHandler) {
 		tmpSize = len / 2
 	}
 
-	h.tmp = make([]ValueType, tmpSize)
+	if cap(h.tmp) < tmpSize {
+		h.tmp = make([]ValueType, tmpSize)
+	}
 
 	/*
 	 * Allocate runs-to-be-merged stack (which cannot be expanded).  The
@@ -140,14 +152,23 @@ func newTimSort(a []ValueType) (h *timSortHandler) {
 		stackLen = 19
 	}
 
-	h.runBase = make([]int, stackLen)
-	h.runLen = make([]int, stackLen)
-
-	return h
+	if cap(h.runBase) < stackLen {
+		h.runBase = make([]int, stackLen)
+		h.runLen = make([]int, stackLen)
+	}
 }
 
 // ValueTypeSort sorts an array using the provided comparator
 func ValueTypeSort(a []ValueType) (err error) {
+	var h timSortHandler
+	if err := h.sort(a); err != nil {
+		return err
+	}
+	return checkSortedValueType(a)
+}
+
+// sort sorts a with TimSort. It keeps buffers in h for the next sort.
+func (h *timSortHandler) sort(a []ValueType) (err error) {
 	lo := 0
 	hi := len(a)
 	nRemaining := hi
@@ -172,7 +193,7 @@ func ValueTypeSort(a []ValueType) (err error) {
 	 * to maintain stack invariant.
 	 */
 
-	ts := newTimSort(a)
+	h.init(a)
 	minRun, err := minRunLength(nRemaining)
 	if err != nil {
 		return
@@ -197,8 +218,8 @@ func ValueTypeSort(a []ValueType) (err error) {
 		}
 
 		// Push run onto pending-run stack, and maybe merge
-		ts.pushRun(lo, runLen)
-		if err = ts.mergeCollapse(); err != nil {
+		h.pushRun(lo, runLen)
+		if err = h.mergeCollapse(); err != nil {
 			return err
 		}
 
@@ -212,18 +233,196 @@ func ValueTypeSort(a []ValueType) (err error) {
 
 	// Merge all remaining runs to complete sort
 	if lo != hi {
-		return errors.New("lo must equal hi")
+		return fmt.Errorf("%w: lo must equal hi", ErrValueTypeInternalInvariant)
 	}
 
-	if err = ts.mergeForceCollapse(); err != nil {
+	if err = h.mergeForceCollapse(); err != nil {
 		return
 	}
-	if ts.stackSize != 1 {
-		return errors.New("ts.stackSize != 1")
+	if h.stackSize != 1 {
+		return fmt.Errorf("%w: h.stackSize != 1", ErrValueTypeInternalInvariant)
 	}
 	return
 }
 
+// ValueTypeSorter sorts slices with TimSort like ValueTypeSort, but keeps its temporary buffers to reuse them
+// in the next sort. It avoids allocation when sorting many slices.
+//
+// A ValueTypeSorter is not safe for concurrent use. To share sorters between goroutines, keep them in sync.Pool
+// and call Reset before putting them back.
+type ValueTypeSorter struct {
+	h timSortHandler
+}
+
+// NewValueTypeSorter returns a ValueTypeSorter.
+func NewValueTypeSorter() *ValueTypeSorter {
+	return &ValueTypeSorter{}
+}
+
+// Sort sorts an array. It returns the same errors as ValueTypeSort.
+func (s *ValueTypeSorter) Sort(a []ValueType) error {
+	err := s.h.sort(a)
+	s.h.a = nil
+	if err != nil {
+		return err
+	}
+	return checkSortedValueType(a)
+}
+
+// Reset clears the temporary buffer so that it doesn't keep items alive. It keeps the capacity of the buffer.
+func (s *ValueTypeSorter) Reset() {
+	var zero ValueType
+	for i := range s.h.tmp {
+		s.h.tmp[i] = zero
+	}
+	s.h.a = nil
+}
+
+// parallelSortMinLengthValueType is the minimum length of a chunk that ValueTypeParallelSort sorts or merges
+// in its own goroutine. Shorter slices are sorted by ValueTypeSort.
+const parallelSortMinLengthValueType = 1 << 12
+
+// ValueTypeParallelSort sorts an array in ascendant order with workers goroutines.
+// If workers is zero or negative, it uses runtime.GOMAXPROCS(0).
+//
+// It splits the array into chunks, sorts each chunk by ValueTypeSort concurrently and merges them in parallel.
+// It is stable like ValueTypeSort and returns the same errors, checking the order after the last merge.
+// It allocates a buffer as large as the array.
+func ValueTypeParallelSort(a []ValueType, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxWorkers := len(a) / parallelSortMinLengthValueType; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers < 2 {
+		return ValueTypeSort(a)
+	}
+	bounds := make([]int, workers+1)
+	for i := range bounds {
+		bounds[i] = len(a) * i / workers
+	}
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = ValueTypeSort(a[bounds[i]:bounds[i+1]])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	// merge neighbor runs pairwise until one run remains, switching source and destination each round
+	src, dst := a, make([]ValueType, len(a))
+	for runs := len(bounds) - 1; runs > 1; runs = len(bounds) - 1 {
+		parts := workers / (runs / 2)
+		next := make([]int, 0, runs/2+2)
+		for i := 0; i < runs; i += 2 {
+			lo := bounds[i]
+			next = append(next, lo)
+			if i+1 == runs {
+				copy(dst[lo:], src[lo:bounds[i+1]])
+				continue
+			}
+			mid, hi := bounds[i+1], bounds[i+2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				parallelMergeValueType(src[lo:mid], src[mid:hi], dst[lo:hi], parts)
+			}()
+		}
+		wg.Wait()
+		bounds = append(next, len(a))
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+	// each chunk passed the check of ValueTypeSort, but a comparator that is inconsistent between chunks
+	// can still leave the merged result out of order
+	return checkSortedValueType(a)
+}
+
+// parallelMergeValueType merges sorted slices a and b into dst stably. It splits the merge into at most parts goroutines.
+func parallelMergeValueType(a, b, dst []ValueType, parts int) {
+	if parts < 2 || len(a)+len(b) < 2*parallelSortMinLengthValueType {
+		mergeValueType(a, b, dst)
+		return
+	}
+	// split the longer slice at the middle. Items of a that are equal to items of b stay in front of them.
+	var i, j int
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		j = ValueTypeLowerBound(b, a[i])
+	} else {
+		j = len(b) / 2
+		i = ValueTypeUpperBound(a, b[j])
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMergeValueType(a[:i], b[:j], dst[:i+j], parts/2)
+	}()
+	parallelMergeValueType(a[i:], b[j:], dst[i+j:], parts-parts/2)
+	wg.Wait()
+}
+
+// mergeValueType merges sorted slices a and b into dst stably.
+func mergeValueType(a, b, dst []ValueType) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// checkSortedValueType returns an error that wraps ErrValueTypeComparatorContract if a is not in order after sort.
+// TimSort doesn't detect all broken comparators by its internal invariants, so ValueTypeSort checks the result at last.
+func checkSortedValueType(a []ValueType) error {
+	for i := range a {
+		if a[i] != a[i] {
+			return fmt.Errorf("%w: item is not equal to itself at %d after sort", ErrValueTypeComparatorContract, i)
+		}
+		if i > 0 && a[i] < a[i-1] {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// ValueTypeValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If ValueTypeSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrValueTypeComparatorContract.
+func ValueTypeValidate(sorted []ValueType) error {
+	for i, item := range sorted {
+		if item != item {
+			return fmt.Errorf("%w: item is not equal to itself at %d", ErrValueTypeComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if item < prev {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
 /**
  * Sorts the specified portion of the specified array using a binary
  * insertion sort.  This is the best method for sorting small numbers
@@ -244,7 +443,7 @@ func ValueTypeSort(a []ValueType) (err error) {
  */
 func binarySort(a []ValueType, lo, hi, start int) (err error) {
 	if lo > start || start > hi {
-		return errors.New("lo <= start && start <= hi")
+		return fmt.Errorf("%w: lo <= start && start <= hi", ErrValueTypeInternalInvariant)
 	}
 
 	if start == lo {
@@ -259,7 +458,7 @@ func binarySort(a []ValueType, lo, hi, start int) (err error) {
 		right := start
 
 		if left > right {
-			return errors.New("left <= right")
+			return fmt.Errorf("%w: left <= right", ErrValueTypeInternalInvariant)
 		}
 
 		/*
@@ -277,7 +476,7 @@ func binarySort(a []ValueType, lo, hi, start int) (err error) {
 		}
 
 		if left != right {
-			return errors.New("left == right")
+			return fmt.Errorf("%w: left == right", ErrValueTypeInternalInvariant)
 		}
 
 		/*
@@ -332,7 +531,7 @@ func binarySort(a []ValueType, lo, hi, start int) (err error) {
 func countRunAndMakeAscending(a []ValueType, lo, hi int) (int, error) {
 
 	if lo >= hi {
-		return 0, errors.New("lo < hi")
+		return 0, fmt.Errorf("%w: lo < hi", ErrValueTypeInternalInvariant)
 	}
 
 	runHi := lo + 1
@@ -392,7 +591,7 @@ func reverseRange(a []ValueType, lo, hi int) {
  */
 func minRunLength(n int) (int, error) {
 	if n < 0 {
-		return 0, errors.New("n >= 0")
+		return 0, fmt.Errorf("%w: n >= 0", ErrValueTypeInternalInvariant)
 	}
 	r := 0 // Becomes 1 if any 1 bits are shifted off
 	for n >= minMerge {
@@ -473,15 +672,15 @@ func (h *timSortHandler) mergeForceCollapse() (err error) {
  */
 func (h *timSortHandler) mergeAt(i int) (err error) {
 	if h.stackSize < 2 {
-		return errors.New("stackSize >= 2")
+		return fmt.Errorf("%w: stackSize >= 2", ErrValueTypeInternalInvariant)
 	}
 
 	if i < 0 {
-		return errors.New(" i >= 0")
+		return fmt.Errorf("%w: i >= 0", ErrValueTypeInternalInvariant)
 	}
 
 	if i != h.stackSize-2 && i != h.stackSize-3 {
-		return errors.New("if i == stackSize - 2 || i == stackSize - 3")
+		return fmt.Errorf("%w: if i == stackSize - 2 || i == stackSize - 3", ErrValueTypeInternalInvariant)
 	}
 
 	base1 := h.runBase[i]
@@ -490,11 +689,11 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 	len2 := h.runLen[i+1]
 
 	if len1 <= 0 || len2 <= 0 {
-		return errors.New("len1 > 0 && len2 > 0")
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0", ErrValueTypeInternalInvariant)
 	}
 
 	if base1+len1 != base2 {
-		return errors.New("base1 + len1 == base2")
+		return fmt.Errorf("%w: base1 + len1 == base2", ErrValueTypeInternalInvariant)
 	}
 
 	/*
@@ -518,7 +717,7 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 		return err
 	}
 	if k < 0 {
-		return errors.New(" k >= 0;")
+		return fmt.Errorf("%w: k >= 0", ErrValueTypeInternalInvariant)
 	}
 	base1 += k
 	len1 -= k
@@ -535,7 +734,7 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 		return
 	}
 	if len2 < 0 {
-		return errors.New(" len2 >= 0;")
+		return fmt.Errorf("%w: len2 >= 0", ErrValueTypeInternalInvariant)
 	}
 	if len2 == 0 {
 		return
@@ -545,12 +744,12 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 	if len1 <= len2 {
 		err = h.mergeLo(base1, len1, base2, len2)
 		if err != nil {
-			return fmt.Errorf("mergeLo: %v", err)
+			return fmt.Errorf("mergeLo: %w", err)
 		}
 	} else {
 		err = h.mergeHi(base1, len1, base2, len2)
 		if err != nil {
-			return fmt.Errorf("mergeHi: %v", err)
+			return fmt.Errorf("mergeHi: %w", err)
 		}
 	}
 	return
@@ -575,7 +774,7 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
  */
 func gallopLeft(key ValueType, a []ValueType, base, len, hint int) (int, error) {
 	if len <= 0 || hint < 0 || hint >= len {
-		return 0, errors.New(" len > 0 && hint >= 0 && hint < len;")
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrValueTypeInternalInvariant)
 	}
 	lastOfs := 0
 	ofs := 1
@@ -618,7 +817,7 @@ func gallopLeft(key ValueType, a []ValueType, base, len, hint int) (int, error)
 	}
 
 	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
-		return 0, errors.New(" -1 <= lastOfs && lastOfs < ofs && ofs <= len;")
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrValueTypeInternalInvariant)
 	}
 
 	/*
@@ -638,7 +837,7 @@ func gallopLeft(key ValueType, a []ValueType, base, len, hint int) (int, error)
 	}
 
 	if lastOfs != ofs {
-		return 0, errors.New(" lastOfs == ofs") // so a[base + ofs - 1] < key <= a[base + ofs]
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrValueTypeInternalInvariant) // so a[base + ofs - 1] < key <= a[base + ofs]
 	}
 	return ofs, nil
 }
@@ -657,7 +856,7 @@ func gallopLeft(key ValueType, a []ValueType, base, len, hint int) (int, error)
  */
 func gallopRight(key ValueType, a []ValueType, base, len, hint int) (int, error) {
 	if len <= 0 || hint < 0 || hint >= len {
-		return 0, errors.New(" len > 0 && hint >= 0 && hint < len;")
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrValueTypeInternalInvariant)
 	}
 
 	ofs := 1
@@ -699,7 +898,7 @@ func gallopRight(key ValueType, a []ValueType, base, len, hint int) (int, error)
 		ofs += hint
 	}
 	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
-		return 0, errors.New("-1 <= lastOfs && lastOfs < ofs && ofs <= len")
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrValueTypeInternalInvariant)
 	}
 
 	/*
@@ -718,7 +917,7 @@ func gallopRight(key ValueType, a []ValueType, base, len, hint int) (int, error)
 		}
 	}
 	if lastOfs != ofs {
-		return 0, errors.New(" lastOfs == ofs") // so a[b + ofs - 1] <= key < a[b + ofs]
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrValueTypeInternalInvariant) // so a[b + ofs - 1] <= key < a[b + ofs]
 	}
 	return ofs, nil
 }
@@ -741,7 +940,7 @@ func gallopRight(key ValueType, a []ValueType, base, len, hint int) (int, error)
  */
 func (h *timSortHandler) mergeLo(base1, len1, base2, len2 int) (err error) {
 	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
-		return errors.New(" len1 > 0 && len2 > 0 && base1 + len1 == base2")
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrValueTypeInternalInvariant)
 	}
 
 	// Copy first run into temp array
@@ -782,7 +981,7 @@ outer:
 		 */
 		for {
 			if len1 <= 1 || len2 <= 0 {
-				return errors.New(" len1 > 1 && len2 > 0")
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrValueTypeInternalInvariant)
 			}
 
 			if a[cursor2] < tmp[cursor1] {
@@ -818,7 +1017,7 @@ outer:
 		 */
 		for {
 			if len1 <= 1 || len2 <= 0 {
-				return errors.New("len1 > 1 && len2 > 0")
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrValueTypeInternalInvariant)
 			}
 			count1, err = gallopRight(a[cursor2], tmp, cursor1, len1, 0)
 			if err != nil {
@@ -880,18 +1079,18 @@ outer:
 	if len1 == 1 {
 
 		if len2 <= 0 {
-			return errors.New(" len2 > 0;")
+			return fmt.Errorf("%w: len2 > 0", ErrValueTypeInternalInvariant)
 		}
 		copy(a[dest:dest+len2], a[cursor2:cursor2+len2])
 		a[dest+len2] = tmp[cursor1] //  Last elt of run 1 to end of merge
 	} else if len1 == 0 {
-		return errors.New("comparison method violates its general contract")
+		return ErrValueTypeComparatorContract
 	} else {
 		if len2 != 0 {
-			return errors.New("len2 == 0;")
+			return fmt.Errorf("%w: len2 == 0", ErrValueTypeInternalInvariant)
 		}
 		if len1 <= 1 {
-			return errors.New(" len1 > 1;")
+			return fmt.Errorf("%w: len1 > 1", ErrValueTypeInternalInvariant)
 		}
 
 		copy(a[dest:dest+len1], tmp[cursor1:cursor1+len1])
@@ -912,7 +1111,7 @@ outer:
  */
 func (h *timSortHandler) mergeHi(base1, len1, base2, len2 int) (err error) {
 	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
-		return errors.New("len1 > 0 && len2 > 0 && base1 + len1 == base2;")
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrValueTypeInternalInvariant)
 	}
 
 	// Copy second run into temp array
@@ -956,7 +1155,7 @@ outer:
 		 */
 		for {
 			if len1 <= 0 || len2 <= 1 {
-				return errors.New(" len1 > 0 && len2 > 1;")
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrValueTypeInternalInvariant)
 			}
 			if tmp[cursor2] < a[cursor1] {
 				a[dest] = a[cursor1]
@@ -991,7 +1190,7 @@ outer:
 		 */
 		for {
 			if len1 <= 0 || len2 <= 1 {
-				return errors.New(" len1 > 0 && len2 > 1;")
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrValueTypeInternalInvariant)
 			}
 			if gr, err := gallopRight(tmp[cursor2], a, base1, len1, len1-1); err == nil {
 				count1 = len1 - gr
@@ -1056,7 +1255,7 @@ outer:
 
 	if len2 == 1 {
 		if len1 <= 0 {
-			return errors.New(" len1 > 0;")
+			return fmt.Errorf("%w: len1 > 0", ErrValueTypeInternalInvariant)
 		}
 		dest -= len1
 		cursor1 -= len1
@@ -1064,14 +1263,14 @@ outer:
 		copy(a[dest+1:dest+1+len1], a[cursor1+1:cursor1+1+len1])
 		a[dest] = tmp[cursor2] // Move first elt of run2 to front of merge
 	} else if len2 == 0 {
-		return errors.New("comparison method violates its general contract")
+		return ErrValueTypeComparatorContract
 	} else {
 		if len1 != 0 {
-			return errors.New("len1 == 0;")
+			return fmt.Errorf("%w: len1 == 0", ErrValueTypeInternalInvariant)
 		}
 
 		if len2 <= 0 {
-			return errors.New(" len2 > 0;")
+			return fmt.Errorf("%w: len2 > 0", ErrValueTypeInternalInvariant)
 		}
 
 		copy(a[dest-(len2-1):dest+1], tmp)
@@ -1131,8 +1330,164 @@ func ValueTypeBinarySearch(sorted []ValueType, item ValueType) int {
 	return i
 }
 
+// ValueTypeLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func ValueTypeLowerBound(sorted []ValueType, item ValueType) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if sorted[h] < item {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// ValueTypeUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func ValueTypeUpperBound(sorted []ValueType, item ValueType) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if !(item < sorted[h]) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopValueType returns first index i that satisfies !(sorted[i] < item) like ValueTypeLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopValueType(sorted []ValueType, item ValueType) int {
+	if len(sorted) == 0 || !(sorted[0] < item) {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && sorted[hi] < item {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + ValueTypeLowerBound(sorted[lo+1:hi], item)
+}
+
+// ValueTypeEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func ValueTypeEqualRange(sorted []ValueType, item ValueType) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, item)
+	hi = lo + ValueTypeUpperBound(sorted[lo:], item)
+	return lo, hi
+}
+
+// ValueTypeCount returns the number of items that are equal to item.
+func ValueTypeCount(sorted []ValueType, item ValueType) int {
+	lo, hi := ValueTypeEqualRange(sorted, item)
+	return hi - lo
+}
+
+// ValueTypeFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeFloor(sorted []ValueType, item ValueType) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeCeiling(sorted []ValueType, item ValueType) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeLower(sorted []ValueType, item ValueType) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeHigher(sorted []ValueType, item ValueType) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func ValueTypeRangeIndexes(sorted []ValueType, from, to ValueType, inclusive bool) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, from)
+	if inclusive {
+		hi = lo + ValueTypeUpperBound(sorted[lo:], to)
+	} else {
+		hi = lo + ValueTypeLowerBound(sorted[lo:], to)
+	}
+	return lo, hi
+}
+
+// ValueTypeRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func ValueTypeRange(sorted []ValueType, from, to ValueType) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like ValueTypeRange.
+func ValueTypeRangeInclusive(sorted []ValueType, from, to ValueType) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRemoveRange removes items in [from, to) and returns a sorted slice.
+func ValueTypeRemoveRange(sorted []ValueType, from, to ValueType) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func ValueTypeRemoveRangeInclusive(sorted []ValueType, from, to ValueType) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeCountRange returns the number of items in [from, to).
+func ValueTypeCountRange(sorted []ValueType, from, to ValueType) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false)
+	return hi - lo
+}
+
+// ValueTypeCountRangeInclusive returns the number of items in [from, to].
+func ValueTypeCountRangeInclusive(sorted []ValueType, from, to ValueType) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true)
+	return hi - lo
+}
+
 // ValueTypeIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
 func ValueTypeIndexOf(sorted []ValueType, item ValueType) int {
+	if len(sorted) == 0 {
+		return -1
+	}
 	i := ValueTypeBinarySearch(sorted, item)
 	if sorted[i] == item {
 		return i
@@ -1142,6 +1497,9 @@ func ValueTypeIndexOf(sorted []ValueType, item ValueType) int {
 
 // ValueTypeContains returns true if item is in a sorted slice. Otherwise false.
 func ValueTypeContains(sorted []ValueType, item ValueType) bool {
+	if len(sorted) == 0 {
+		return false
+	}
 	i := ValueTypeBinarySearch(sorted, item)
 	return sorted[i] == item
 }
@@ -1155,8 +1513,63 @@ func ValueTypeInsert(sorted []ValueType, item ValueType) []ValueType {
 	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...)
 }
 
+// ValueTypeInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling ValueTypeInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func ValueTypeInsertAll(sorted []ValueType, items []ValueType) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]ValueType, len(items))
+	copy(batch, items)
+	ValueTypeSort(batch)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && batch[j] < sorted[i] {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]ValueType, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if batch[j] < sorted[i] {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// ValueTypeInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func ValueTypeInsertUnique(sorted []ValueType, item ValueType) ([]ValueType, bool) {
+	i := ValueTypeLowerBound(sorted, item)
+	if i < len(sorted) && sorted[i] == item {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...), true
+}
+
 // ValueTypeRemove removes item in a sorted slice.
 func ValueTypeRemove(sorted []ValueType, item ValueType) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
 	i := ValueTypeBinarySearch(sorted, item)
 	if sorted[i] == item {
 		return ValueTypeRemoveAt(sorted, i)
@@ -1169,54 +1582,186 @@ func ValueTypeRemoveAt(sorted []ValueType, i int) []ValueType {
 	return append(sorted[:i], sorted[i+1:]...)
 }
 
+// ValueTypeRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveAll(sorted []ValueType, items []ValueType) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && items[j] < item {
+			j++
+		}
+		if j < len(items) && items[j] == item {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveIf(sorted []ValueType, pred func(item ValueType) bool) []ValueType {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func ValueTypeRemoveIndexes(sorted []ValueType, indexes []int) []ValueType {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func ValueTypeUnique(sorted []ValueType) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if result[len(result)-1] < item {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func ValueTypeUniqueFunc(sorted []ValueType, equal func(a, b ValueType) bool) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdValueType is the number of source slices that ValueTypeIterateOver and ValueTypeUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdValueType = 8
+
 // ValueTypeIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
 func ValueTypeIterateOver(callback func(item ValueType, srcIndex int), sorted ...[]ValueType) {
 	sourceSlices := make([][]ValueType, 0, len(sorted))
-	for _, src := range sorted {
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
 		if len(src) > 0 {
 			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
 		}
 	}
-	sourceSliceCount := len(sourceSlices)
-	if sourceSliceCount == 0 {
-		return
-	} else if sourceSliceCount == 1 {
-		for i, value := range sourceSlices[0] {
-			callback(value, i)
-		}
-		return
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		iterateOverHeapValueType(callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearValueType(callback, sourceSlices, sliceIndex)
 	}
-	indexes := make([]int, sourceSliceCount)
-	sliceIndex := make([]int, sourceSliceCount)
-	for i := range sourceSlices {
-		sliceIndex[i] = i
+}
+
+// iterateOverLinearValueType finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapValueType when there are a few source slices.
+func iterateOverLinearValueType(callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
 	}
-	index := 0
-	for {
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
 		minSlice := 0
 		minItem := sourceSlices[0][indexes[0]]
-		for i := 1; i < sourceSliceCount; i++ {
+		for i := 1; i < len(sourceSlices); i++ {
 			if sourceSlices[i][indexes[i]] < minItem {
 				minSlice = i
 				minItem = sourceSlices[i][indexes[i]]
 			}
 		}
 		callback(minItem, sliceIndex[minSlice])
-		index++
 		indexes[minSlice]++
 		if indexes[minSlice] == len(sourceSlices[minSlice]) {
 			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
 			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
 			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
-			sourceSliceCount--
-			if len(sourceSlices) == 1 {
-				slice := sourceSlices[0]
-				for i := indexes[0]; i < len(slice); i++ {
-					callback(slice[i], sliceIndex[0])
-				}
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapValueType keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearValueType takes O(N k).
+func iterateOverHeapValueType(callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if x != y {
+			return x < y
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
 				return
 			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
 		}
+		down(0)
 	}
 }
 
@@ -1235,6 +1780,13 @@ func ValueTypeUnion(sorted ...[]ValueType) []ValueType {
 	} else if len(sourceSlices) == 1 {
 		return sourceSlices[0]
 	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		result := make([]ValueType, 0, length)
+		ValueTypeIterateOver(func(item ValueType, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
 	result := make([]ValueType, length)
 	sourceSliceCount := len(sourceSlices)
 	indexes := make([]int, sourceSliceCount)
@@ -1263,7 +1815,46 @@ func ValueTypeUnion(sorted ...[]ValueType) []ValueType {
 	}
 }
 
+// ValueTypeUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func ValueTypeUnionDistinct(sorted ...[]ValueType) []ValueType {
+	var result []ValueType
+	ValueTypeIterateOver(func(item ValueType, srcIndex int) {
+		if len(result) == 0 || result[len(result)-1] < item {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// ValueTypeDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
 func ValueTypeDifference(sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopValueType(sorted1[i:], sorted2[j])
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i])
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if sorted1[i] == sorted2[j] {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// ValueTypeSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func ValueTypeSymmetricDifference(sorted1, sorted2 []ValueType) []ValueType {
 	var result []ValueType
 	var i, j int
 	for i < len(sorted1) && j < len(sorted2) {
@@ -1271,6 +1862,7 @@ func ValueTypeDifference(sorted1, sorted2 []ValueType) []ValueType {
 			result = append(result, sorted1[i])
 			i++
 		} else if sorted2[j] < sorted1[i] {
+			result = append(result, sorted2[j])
 			j++
 		} else {
 			i++
@@ -1278,49 +1870,551 @@ func ValueTypeDifference(sorted1, sorted2 []ValueType) []ValueType {
 		}
 	}
 	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
 	return result
 }
 
+// ValueTypeDifferenceAll creates difference group of base and all subtract slices and returns.
+func ValueTypeDifferenceAll(base []ValueType, subtract ...[]ValueType) []ValueType {
+	return ValueTypeDifference(base, ValueTypeUnion(subtract...))
+}
+
+// ValueTypeDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func ValueTypeDiff(old, new []ValueType) (added, removed, common []ValueType) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		if old[i] < new[j] {
+			removed = append(removed, old[i])
+			i++
+		} else if new[j] < old[i] {
+			added = append(added, new[j])
+			j++
+		} else {
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// ValueTypeIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func ValueTypeIsSubset(sub, super []ValueType) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopValueType(super[j:], item)
+		if j == len(super) || item < super[j] {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// ValueTypeIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func ValueTypeIsSuperset(super, sub []ValueType) bool {
+	return ValueTypeIsSubset(sub, super)
+}
+
+// ValueTypeIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func ValueTypeIsDisjoint(sorted1, sorted2 []ValueType) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopValueType(sorted1[i:], sorted2[j])
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i])
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if sorted1[i] == sorted2[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func ValueTypeEqual(sorted1, sorted2 []ValueType) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if sorted1[i] != sorted2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeIntersectionSize returns the number of items that ValueTypeIntersection returns for sorted1 and sorted2
+// without allocating memory. Like ValueTypeIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func ValueTypeIntersectionSize(sorted1, sorted2 []ValueType) int {
+	// ValueTypeIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopValueType(long[j:], item)
+		if j == len(long) {
+			break
+		}
+		if item == long[j] {
+			count++
+		}
+	}
+	return count
+}
+
+// ValueTypeIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
 func ValueTypeIntersection(sorted ...[]ValueType) []ValueType {
-	sort.Slice(sorted, func(i, j int) bool {
-		return len(sorted[i]) < len(sorted[j])
+	return ValueTypeIntersectionFunc(nil, sorted...)
+}
+
+// ValueTypeIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func ValueTypeIntersectionFunc(callback func(item ValueType, indexes []int), sorted ...[]ValueType) []ValueType {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
 	})
+	if len(sorted) > heapMergeThresholdValueType {
+		return intersectionHeapValueType(callback, sorted, order[0])
+	}
+	return intersectionLinearValueType(callback, sorted, order)
+}
+
+// intersectionLinearValueType checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapValueType when there are a few source slices or the items are spread evenly.
+func intersectionLinearValueType(callback func(item ValueType, indexes []int), sorted [][]ValueType, order []int) []ValueType {
 	var result []ValueType
-	if len(sorted[0]) == 0 {
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopValueType(src[cursors[i]:], value)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if value < src[cursors[i]] {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapValueType keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearValueType when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapValueType(callback func(item ValueType, indexes []int), sorted [][]ValueType, shortest int) []ValueType {
+	var result []ValueType
+	if len(sorted[shortest]) == 0 {
 		return result
 	}
 	cursors := make([]int, len(sorted))
-	terminate := false
-	for _, value := range sorted[0] {
-		needIncrement := false
-		for i := 1; i < len(sorted); i++ {
-			found := false
-			for j := cursors[i]; j < len(sorted[i]); j++ {
-				valueOfOtherSlice := sorted[i][cursors[i]]
-				if valueOfOtherSlice < value {
-					cursors[i] = j + 1
-				} else if value < valueOfOtherSlice {
-					needIncrement = true
-					break
-				} else {
-					found = true
-					break
-				}
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if maxItem < sorted[i][0] {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return sorted[a][cursors[a]] < sorted[b][cursors[b]]
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
 			}
-			if needIncrement {
-				break
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if sorted[src][cursors[src]] < maxItem {
+			cursors[src] += gallopValueType(sorted[src][cursors[src]:], maxItem)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; maxItem < item {
+				maxItem = item
 			}
-			if !found {
-				terminate = true
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && !(maxItem < s[cursors[shortest]]) {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
+			}
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
 				break
 			}
 		}
-		if terminate {
-			break
+	}
+}
+
+// ValueTypeSortedSet is a set that keeps unique items in a sorted slice.
+type ValueTypeSortedSet struct {
+	items []ValueType
+}
+
+// NewValueTypeSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewValueTypeSortedSet(items ...ValueType) *ValueTypeSortedSet {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || unique[len(unique)-1] != item {
+			unique = append(unique, item)
 		}
-		if !needIncrement {
-			result = append(result, value)
+	}
+	return &ValueTypeSortedSet{items: unique}
+}
+
+// search returns first index i that satisfies item <= s.items[i]. If there is no such item, it returns s.Len().
+func (s *ValueTypeSortedSet) search(item ValueType) int {
+	i, j := 0, len(s.items)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if s.items[h] < item {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *ValueTypeSortedSet) Add(item ValueType) bool {
+	i := s.search(item)
+	if i < len(s.items) && s.items[i] == item {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *ValueTypeSortedSet) Delete(item ValueType) bool {
+	i := s.search(item)
+	if i == len(s.items) || s.items[i] != item {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *ValueTypeSortedSet) Has(item ValueType) bool {
+	i := s.search(item)
+	return i < len(s.items) && s.items[i] == item
+}
+
+// Len returns the number of items in the set.
+func (s *ValueTypeSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *ValueTypeSortedSet) At(i int) ValueType {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *ValueTypeSortedSet) Range(callback func(i int, item ValueType) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *ValueTypeSortedSet) Slice() []ValueType {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *ValueTypeSortedSet) Union(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	result := make([]ValueType, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.items[i] < other.items[j] {
+			result = append(result, s.items[i])
+			i++
+		} else if other.items[j] < s.items[i] {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedSet{items: result}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *ValueTypeSortedSet) Intersect(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.items[i] < other.items[j] {
+			i++
+		} else if other.items[j] < s.items[i] {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedSet{items: result}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *ValueTypeSortedSet) Subtract(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.items[i] < other.items[j] {
+			result = append(result, s.items[i])
+			i++
+		} else if other.items[j] < s.items[i] {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &ValueTypeSortedSet{items: result}
+}
+
+// ValueTypeSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type ValueTypeSortedMultiset struct {
+	items []ValueType
+}
+
+// NewValueTypeSortedMultiset creates a sorted multiset that has items.
+func NewValueTypeSortedMultiset(items ...ValueType) *ValueTypeSortedMultiset {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted)
+	return &ValueTypeSortedMultiset{items: sorted}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *ValueTypeSortedMultiset) equalRange(item ValueType) (lo, hi int) {
+	return ValueTypeEqualRange(m.items, item)
+}
+
+// Add adds item to the multiset.
+func (m *ValueTypeSortedMultiset) Add(item ValueType) {
+	m.items = ValueTypeInsert(m.items, item)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *ValueTypeSortedMultiset) RemoveOne(item ValueType) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = ValueTypeRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *ValueTypeSortedMultiset) RemoveAll(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *ValueTypeSortedMultiset) Count(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *ValueTypeSortedMultiset) Has(item ValueType) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *ValueTypeSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *ValueTypeSortedMultiset) Distinct() []ValueType {
+	var result []ValueType
+	for i, item := range m.items {
+		if i == 0 || result[len(result)-1] != item {
+			result = append(result, item)
 		}
 	}
 	return result
 }
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *ValueTypeSortedMultiset) Range(callback func(item ValueType, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && m.items[lo] == m.items[hi] {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *ValueTypeSortedMultiset) Slice() []ValueType {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *ValueTypeSortedMultiset) Union(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	result := make([]ValueType, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.items[i] < other.items[j] {
+			result = append(result, m.items[i])
+			i++
+		} else if other.items[j] < m.items[i] {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedMultiset{items: result}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *ValueTypeSortedMultiset) Intersect(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.items[i] < other.items[j] {
+			i++
+		} else if other.items[j] < m.items[i] {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedMultiset{items: result}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *ValueTypeSortedMultiset) Subtract(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.items[i] < other.items[j] {
+			result = append(result, m.items[i])
+			i++
+		} else if other.items[j] < m.items[i] {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &ValueTypeSortedMultiset{items: result}
+}
@@ -0,0 +1,54 @@
+package template_comparable
+
+import (
+	"unsafe"
+)
+
+// Radix sort: these functions are generated by slicesgen -radix when ValueType is an integer type.
+// They sort integers without comparison, so they are faster than ValueTypeSort for large slices.
+
+// radixSortMinLengthValueType is the minimum length of a slice that ValueTypeRadixSort sorts with radix sort.
+// Shorter slices are sorted by ValueTypeSort.
+const radixSortMinLengthValueType = 256
+
+// ValueTypeRadixSort sorts an array of integers in ascendant order with LSD radix sort.
+// It sorts a byte of items in each pass and skips passes that all items have the same byte,
+// so small values are sorted in fewer passes. It allocates a buffer as large as the array.
+func ValueTypeRadixSort(a []ValueType) (err error) {
+	if len(a) < radixSortMinLengthValueType {
+		return ValueTypeSort(a)
+	}
+	var zero ValueType
+	bits := uint(unsafe.Sizeof(zero)) * 8
+	// flip the sign bit of signed integers so that negative values come first in unsigned order
+	var signBit uint64
+	if zero-1 < zero {
+		signBit = 1 << (bits - 1)
+	}
+	src, dst := a, make([]ValueType, len(a))
+	var offsets [256]int
+	for shift := uint(0); shift < bits; shift += 8 {
+		offsets = [256]int{}
+		for _, v := range src {
+			offsets[byte((uint64(v)^signBit)>>shift)]++
+		}
+		if offsets[byte((uint64(src[0])^signBit)>>shift)] == len(src) {
+			continue
+		}
+		sum := 0
+		for i, count := range offsets {
+			offsets[i] = sum
+			sum += count
+		}
+		for _, v := range src {
+			b := byte((uint64(v) ^ signBit) >> shift)
+			dst[offsets[b]] = v
+			offsets[b]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+	return nil
+}
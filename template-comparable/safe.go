@@ -0,0 +1,199 @@
+package template_comparable
+
+import (
+	"errors"
+	"fmt"
+)
+
+// slicesgen -safe appends this file to the comparable template. Items are compared with <, so ValueTypeValidate
+// that checks the sorted input of ValueTypeSafeInsert and the set operations also rejects NaN.
+
+// ErrValueTypeEmpty is returned when a sorted slice is empty.
+var ErrValueTypeEmpty = errors.New("slice is empty")
+
+// ErrValueTypeNotFound is returned when an item is not in a sorted slice.
+var ErrValueTypeNotFound = errors.New("item is not found")
+
+// ErrValueTypeOutOfRange is returned when an index is out of range of a slice.
+var ErrValueTypeOutOfRange = errors.New("index is out of range")
+
+// ErrValueTypeInvalidRange is returned when the end of a range is less than its start.
+var ErrValueTypeInvalidRange = errors.New("range is invalid")
+
+// ValueTypeSafeBinarySearch returns first index i that satisfies slices[i] <= item.
+// It returns ErrValueTypeEmpty if a sorted slice is empty.
+func ValueTypeSafeBinarySearch(sorted []ValueType, item ValueType) (int, error) {
+	if len(sorted) == 0 {
+		return -1, ErrValueTypeEmpty
+	}
+	return ValueTypeBinarySearch(sorted, item), nil
+}
+
+// ValueTypeSafeIndexOf returns index of item. It returns ErrValueTypeNotFound if item is not in a sorted slice.
+func ValueTypeSafeIndexOf(sorted []ValueType, item ValueType) (int, error) {
+	i := ValueTypeIndexOf(sorted, item)
+	if i == -1 {
+		return -1, ErrValueTypeNotFound
+	}
+	return i, nil
+}
+
+// ValueTypeSafeRemove removes item in a sorted slice. It returns ErrValueTypeNotFound if item is not in a sorted slice.
+func ValueTypeSafeRemove(sorted []ValueType, item ValueType) ([]ValueType, error) {
+	i := ValueTypeIndexOf(sorted, item)
+	if i == -1 {
+		return sorted, ErrValueTypeNotFound
+	}
+	return ValueTypeRemoveAt(sorted, i), nil
+}
+
+// ValueTypeSafeRemoveAt removes item at the specified index in a slice.
+// It returns an error that wraps ErrValueTypeOutOfRange if the index is out of range.
+func ValueTypeSafeRemoveAt(sorted []ValueType, i int) ([]ValueType, error) {
+	if i < 0 || i >= len(sorted) {
+		return sorted, fmt.Errorf("%w: %d (length %d)", ErrValueTypeOutOfRange, i, len(sorted))
+	}
+	return ValueTypeRemoveAt(sorted, i), nil
+}
+
+// ValueTypeSafeRemoveIndexes removes items at indexes from a sorted slice.
+// It returns an error that wraps ErrValueTypeOutOfRange without modifying the slice if any index is out of range.
+func ValueTypeSafeRemoveIndexes(sorted []ValueType, indexes []int) ([]ValueType, error) {
+	for _, i := range indexes {
+		if i < 0 || i >= len(sorted) {
+			return sorted, fmt.Errorf("%w: %d (length %d)", ErrValueTypeOutOfRange, i, len(sorted))
+		}
+	}
+	return ValueTypeRemoveIndexes(sorted, indexes), nil
+}
+
+// ValueTypeSafeFloor returns index of the greatest item that is less than or equal to item.
+// It returns ErrValueTypeNotFound if there is no such item.
+func ValueTypeSafeFloor(sorted []ValueType, item ValueType) (int, error) {
+	i, ok := ValueTypeFloor(sorted, item)
+	if !ok {
+		return -1, ErrValueTypeNotFound
+	}
+	return i, nil
+}
+
+// ValueTypeSafeCeiling returns index of the smallest item that is greater than or equal to item.
+// It returns ErrValueTypeNotFound if there is no such item.
+func ValueTypeSafeCeiling(sorted []ValueType, item ValueType) (int, error) {
+	i, ok := ValueTypeCeiling(sorted, item)
+	if !ok {
+		return -1, ErrValueTypeNotFound
+	}
+	return i, nil
+}
+
+// ValueTypeSafeLower returns index of the greatest item that is strictly less than item.
+// It returns ErrValueTypeNotFound if there is no such item.
+func ValueTypeSafeLower(sorted []ValueType, item ValueType) (int, error) {
+	i, ok := ValueTypeLower(sorted, item)
+	if !ok {
+		return -1, ErrValueTypeNotFound
+	}
+	return i, nil
+}
+
+// ValueTypeSafeHigher returns index of the smallest item that is strictly greater than item.
+// It returns ErrValueTypeNotFound if there is no such item.
+func ValueTypeSafeHigher(sorted []ValueType, item ValueType) (int, error) {
+	i, ok := ValueTypeHigher(sorted, item)
+	if !ok {
+		return -1, ErrValueTypeNotFound
+	}
+	return i, nil
+}
+
+// ValueTypeSafeRange returns items in [from, to) of a sorted slice like ValueTypeRange.
+// It returns an error that wraps ErrValueTypeInvalidRange if to is less than from.
+func ValueTypeSafeRange(sorted []ValueType, from, to ValueType) ([]ValueType, error) {
+	if to < from {
+		return nil, fmt.Errorf("%w: to is less than from", ErrValueTypeInvalidRange)
+	}
+	return ValueTypeRange(sorted, from, to), nil
+}
+
+// ValueTypeSafeRangeInclusive returns items in [from, to] of a sorted slice like ValueTypeRangeInclusive.
+// It returns an error that wraps ErrValueTypeInvalidRange if to is less than from.
+func ValueTypeSafeRangeInclusive(sorted []ValueType, from, to ValueType) ([]ValueType, error) {
+	if to < from {
+		return nil, fmt.Errorf("%w: to is less than from", ErrValueTypeInvalidRange)
+	}
+	return ValueTypeRangeInclusive(sorted, from, to), nil
+}
+
+// ValueTypeSafeRemoveRange removes items in [from, to) like ValueTypeRemoveRange.
+// It returns an error that wraps ErrValueTypeInvalidRange without modifying the slice if to is less than from.
+func ValueTypeSafeRemoveRange(sorted []ValueType, from, to ValueType) ([]ValueType, error) {
+	if to < from {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrValueTypeInvalidRange)
+	}
+	return ValueTypeRemoveRange(sorted, from, to), nil
+}
+
+// ValueTypeSafeRemoveRangeInclusive removes items in [from, to] like ValueTypeRemoveRangeInclusive.
+// It returns an error that wraps ErrValueTypeInvalidRange without modifying the slice if to is less than from.
+func ValueTypeSafeRemoveRangeInclusive(sorted []ValueType, from, to ValueType) ([]ValueType, error) {
+	if to < from {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrValueTypeInvalidRange)
+	}
+	return ValueTypeRemoveRangeInclusive(sorted, from, to), nil
+}
+
+// ValueTypeSafeInsert inserts item in a sorted slice like ValueTypeInsert. Insert into an unsorted slice
+// breaks the order silently, so it returns an error that wraps ErrValueTypeComparatorContract without modifying the slice.
+func ValueTypeSafeInsert(sorted []ValueType, item ValueType) ([]ValueType, error) {
+	if err := ValueTypeValidate(sorted); err != nil {
+		return sorted, err
+	}
+	return ValueTypeInsert(sorted, item), nil
+}
+
+// ValueTypeSafeUnion merges sorted slices like ValueTypeUnion.
+// It returns an error that wraps ErrValueTypeComparatorContract if any slice is not sorted.
+func ValueTypeSafeUnion(sorted ...[]ValueType) ([]ValueType, error) {
+	if err := validateSourcesValueType(sorted); err != nil {
+		return nil, err
+	}
+	return ValueTypeUnion(sorted...), nil
+}
+
+// ValueTypeSafeIntersection returns common items of sorted slices like ValueTypeIntersection.
+// It returns an error that wraps ErrValueTypeComparatorContract if any slice is not sorted.
+func ValueTypeSafeIntersection(sorted ...[]ValueType) ([]ValueType, error) {
+	if err := validateSourcesValueType(sorted); err != nil {
+		return nil, err
+	}
+	return ValueTypeIntersection(sorted...), nil
+}
+
+// ValueTypeSafeDifference returns items of sorted1 that are not in sorted2 like ValueTypeDifference.
+// It returns an error that wraps ErrValueTypeComparatorContract if any slice is not sorted.
+func ValueTypeSafeDifference(sorted1, sorted2 []ValueType) ([]ValueType, error) {
+	if err := validateSourcesValueType([][]ValueType{sorted1, sorted2}); err != nil {
+		return nil, err
+	}
+	return ValueTypeDifference(sorted1, sorted2), nil
+}
+
+// ValueTypeSafeSymmetricDifference returns items that are in only one of sorted slices like ValueTypeSymmetricDifference.
+// It returns an error that wraps ErrValueTypeComparatorContract if any slice is not sorted.
+func ValueTypeSafeSymmetricDifference(sorted1, sorted2 []ValueType) ([]ValueType, error) {
+	if err := validateSourcesValueType([][]ValueType{sorted1, sorted2}); err != nil {
+		return nil, err
+	}
+	return ValueTypeSymmetricDifference(sorted1, sorted2), nil
+}
+
+// validateSourcesValueType validates each source slice of the set operations and tells which slice is broken.
+func validateSourcesValueType(sorted [][]ValueType) error {
+	for i, s := range sorted {
+		if err := ValueTypeValidate(s); err != nil {
+			return fmt.Errorf("slice %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,2069 @@
+package template_compare_timsort
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cheekybits/genny/generic"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+type ValueType generic.Type
+
+// Package timsort provides fast stable sort, uses external comparator.
+//
+// A stable, adaptive, iterative mergesort that requires far fewer than
+// n lg(n) comparisons when running on partially sorted arrays, while
+// offering performance comparable to a traditional mergesort when run
+// on random arrays.  Like all proper mergesorts, this sort is stable and
+// runs O(n log n) time (worst case).  In the worst case, this sort requires
+// temporary storage space for n/2 object references; in the best case,
+// it requires only a small constant amount of space.
+//
+// This implementation was derived from Java's TimSort object by Josh Bloch,
+// which, in turn, was based on the original code by Tim Peters:
+//
+// http://svn.python.org/projects/python/trunk/Objects/listsort.txt
+//
+// Mike K.
+
+// ValueTypeCompare is Delegate type that sorting uses as a comparator.
+// It returns a negative number when a < b, a positive number when a > b and zero when a == b.
+type ValueTypeCompare func(a, b ValueType) int
+
+// ErrValueTypeComparatorContract is returned when a comparator doesn't satisfy the contract of strict weak ordering
+// (for example, compare(a, a) != 0 or the sort result is not in order). Use errors.Is to check it.
+var ErrValueTypeComparatorContract = errors.New("comparison method violates its general contract")
+
+// ErrValueTypeInternalInvariant is returned when TimSort detects a broken internal invariant in the middle of sort.
+// It is usually caused by a comparator that returns inconsistent results. Use errors.Is to check it.
+var ErrValueTypeInternalInvariant = errors.New("internal invariant of sort is broken")
+
+type timSortHandler struct {
+	a         []ValueType
+	compare   ValueTypeCompare
+	minGallop int
+	tmp       []ValueType // Actual runtime type will be Object[], regardless of ValueType
+	stackSize int         // Number of pending runs on stack
+	runBase   []int
+	runLen    []int
+}
+
+// init prepares h to sort a. Buffers of the previous sort are reused if they are large enough.
+func (h *timSortHandler) init(a []ValueType, compare ValueTypeCompare) {
+	const initialTmpStorageLength = 256
+	h.a = a
+	h.compare = compare
+	h.minGallop = 7
+	h.stackSize = 0
+
+	len := len(a)
+
+	tmpSize := initialTmpStorageLength
+	if len < 2*tmpSize {
+		tmpSize = len / 2
+	}
+
+	if cap(h.tmp) < tmpSize {
+		h.tmp = make([]ValueType, tmpSize)
+	}
+	stackLen := 40
+	if len < 120 {
+		stackLen = 5
+	} else if len < 1542 {
+		stackLen = 10
+	} else if len < 119151 {
+		stackLen = 19
+	}
+
+	if cap(h.runBase) < stackLen {
+		h.runBase = make([]int, stackLen)
+		h.runLen = make([]int, stackLen)
+	}
+}
+
+// ValueTypeSort sorts an array using the provided comparator
+func ValueTypeSort(a []ValueType, compare ValueTypeCompare) (err error) {
+	var h timSortHandler
+	if err := h.sort(a, compare); err != nil {
+		return err
+	}
+	return checkSortedValueType(a, compare)
+}
+
+// sort sorts a with TimSort. It keeps buffers in h for the next sort.
+func (h *timSortHandler) sort(a []ValueType, compare ValueTypeCompare) (err error) {
+	const minMerge = 32
+	lo := 0
+	hi := len(a)
+	nRemaining := hi
+	if nRemaining < 2 {
+		return
+	}
+	if nRemaining < minMerge {
+		initRunLen, err := countRunAndMakeAscending(a, lo, hi, compare)
+		if err != nil {
+			return err
+		}
+		return binarySort(a, lo, hi, lo+initRunLen, compare)
+	}
+	h.init(a, compare)
+	minRun, err := minRunLength(nRemaining)
+	if err != nil {
+		return
+	}
+	for {
+		runLen, err := countRunAndMakeAscending(a, lo, hi, compare)
+		if err != nil {
+			return err
+		}
+		if runLen < minRun {
+			force := minRun
+			if nRemaining <= minRun {
+				force = nRemaining
+			}
+			if err = binarySort(a, lo, lo+force, lo+runLen, compare); err != nil {
+				return err
+			}
+			runLen = force
+		}
+		h.pushRun(lo, runLen)
+		if err = h.mergeCollapse(); err != nil {
+			return err
+		}
+		lo += runLen
+		nRemaining -= runLen
+		if nRemaining == 0 {
+			break
+		}
+	}
+	if lo != hi {
+		return fmt.Errorf("%w: lo must equal hi", ErrValueTypeInternalInvariant)
+	}
+	if err = h.mergeForceCollapse(); err != nil {
+		return
+	}
+	if h.stackSize != 1 {
+		return fmt.Errorf("%w: h.stackSize != 1", ErrValueTypeInternalInvariant)
+	}
+	return
+}
+
+// ValueTypeSorter sorts slices with TimSort like ValueTypeSort, but keeps its temporary buffers to reuse them
+// in the next sort. It avoids allocation when sorting many slices.
+//
+// A ValueTypeSorter is not safe for concurrent use. To share sorters between goroutines, keep them in sync.Pool
+// and call Reset before putting them back.
+type ValueTypeSorter struct {
+	h       timSortHandler
+	compare ValueTypeCompare
+}
+
+// NewValueTypeSorter returns a ValueTypeSorter that uses the provided comparator.
+func NewValueTypeSorter(compare ValueTypeCompare) *ValueTypeSorter {
+	return &ValueTypeSorter{compare: compare}
+}
+
+// Sort sorts an array. It returns the same errors as ValueTypeSort.
+func (s *ValueTypeSorter) Sort(a []ValueType) error {
+	err := s.h.sort(a, s.compare)
+	s.h.a = nil
+	if err != nil {
+		return err
+	}
+	return checkSortedValueType(a, s.compare)
+}
+
+// Reset clears the temporary buffer so that it doesn't keep items alive. It keeps the capacity of the buffer.
+func (s *ValueTypeSorter) Reset() {
+	var zero ValueType
+	for i := range s.h.tmp {
+		s.h.tmp[i] = zero
+	}
+	s.h.a = nil
+}
+
+// parallelSortMinLengthValueType is the minimum length of a chunk that ValueTypeParallelSort sorts or merges
+// in its own goroutine. Shorter slices are sorted by ValueTypeSort.
+const parallelSortMinLengthValueType = 1 << 12
+
+// ValueTypeParallelSort sorts an array using the provided comparator with workers goroutines.
+// If workers is zero or negative, it uses runtime.GOMAXPROCS(0).
+//
+// It splits the array into chunks, sorts each chunk by ValueTypeSort concurrently and merges them in parallel.
+// It is stable like ValueTypeSort and returns the same errors, checking the order after the last merge.
+// It allocates a buffer as large as the array.
+func ValueTypeParallelSort(a []ValueType, compare ValueTypeCompare, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxWorkers := len(a) / parallelSortMinLengthValueType; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers < 2 {
+		return ValueTypeSort(a, compare)
+	}
+	bounds := make([]int, workers+1)
+	for i := range bounds {
+		bounds[i] = len(a) * i / workers
+	}
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = ValueTypeSort(a[bounds[i]:bounds[i+1]], compare)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	// merge neighbor runs pairwise until one run remains, switching source and destination each round
+	src, dst := a, make([]ValueType, len(a))
+	for runs := len(bounds) - 1; runs > 1; runs = len(bounds) - 1 {
+		parts := workers / (runs / 2)
+		next := make([]int, 0, runs/2+2)
+		for i := 0; i < runs; i += 2 {
+			lo := bounds[i]
+			next = append(next, lo)
+			if i+1 == runs {
+				copy(dst[lo:], src[lo:bounds[i+1]])
+				continue
+			}
+			mid, hi := bounds[i+1], bounds[i+2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				parallelMergeValueType(src[lo:mid], src[mid:hi], dst[lo:hi], compare, parts)
+			}()
+		}
+		wg.Wait()
+		bounds = append(next, len(a))
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+	// each chunk passed the check of ValueTypeSort, but a comparator that is inconsistent between chunks
+	// can still leave the merged result out of order
+	return checkSortedValueType(a, compare)
+}
+
+// parallelMergeValueType merges sorted slices a and b into dst stably. It splits the merge into at most parts goroutines.
+func parallelMergeValueType(a, b, dst []ValueType, compare ValueTypeCompare, parts int) {
+	if parts < 2 || len(a)+len(b) < 2*parallelSortMinLengthValueType {
+		mergeValueType(a, b, dst, compare)
+		return
+	}
+	// split the longer slice at the middle. Items of a that are equal to items of b stay in front of them.
+	var i, j int
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		j = ValueTypeLowerBound(b, a[i], compare)
+	} else {
+		j = len(b) / 2
+		i = ValueTypeUpperBound(a, b[j], compare)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMergeValueType(a[:i], b[:j], dst[:i+j], compare, parts/2)
+	}()
+	parallelMergeValueType(a[i:], b[j:], dst[i+j:], compare, parts-parts/2)
+	wg.Wait()
+}
+
+// mergeValueType merges sorted slices a and b into dst stably.
+func mergeValueType(a, b, dst []ValueType, compare ValueTypeCompare) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if compare(b[j], a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// checkSortedValueType returns an error that wraps ErrValueTypeComparatorContract if a is not in order after sort.
+// TimSort doesn't detect all broken comparators by its internal invariants, so ValueTypeSort checks the result at last.
+func checkSortedValueType(a []ValueType, compare ValueTypeCompare) error {
+	for i := 1; i < len(a); i++ {
+		if compare(a[i], a[i-1]) < 0 {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// ValueTypeValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If ValueTypeSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrValueTypeComparatorContract.
+func ValueTypeValidate(sorted []ValueType, compare ValueTypeCompare) error {
+	for i, item := range sorted {
+		if compare(item, item) != 0 {
+			return fmt.Errorf("%w: item is not equal to itself at %d", ErrValueTypeComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if compare(item, prev) < 0 {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrValueTypeComparatorContract, i-1, i)
+		}
+		if c1, c2 := compare(prev, item), compare(item, prev); (c1 < 0) != (c2 > 0) || (c1 == 0) != (c2 == 0) {
+			return fmt.Errorf("%w: comparison results of items at %d and %d are not antisymmetric", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+func binarySort(a []ValueType, lo, hi, start int, compare ValueTypeCompare) (err error) {
+	if lo > start || start > hi {
+		return fmt.Errorf("%w: lo <= start && start <= hi", ErrValueTypeInternalInvariant)
+	}
+
+	if start == lo {
+		start++
+	}
+
+	for ; start < hi; start++ {
+		pivot := a[start]
+		left := lo
+		right := start
+		if left > right {
+			return fmt.Errorf("%w: left <= right", ErrValueTypeInternalInvariant)
+		}
+		for left < right {
+			mid := int(uint(left+right) >> 1)
+			if compare(pivot, a[mid]) < 0 {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+		if left != right {
+			return fmt.Errorf("%w: left == right", ErrValueTypeInternalInvariant)
+		}
+		n := start - left // The number of elements to move
+		if n <= 2 {
+			if n == 2 {
+				a[left+2] = a[left+1]
+			}
+			if n > 0 {
+				a[left+1] = a[left]
+			}
+		} else {
+			copy(a[left+1:], a[left:left+n])
+		}
+		a[left] = pivot
+	}
+	return
+}
+
+func countRunAndMakeAscending(a []ValueType, lo, hi int, compare ValueTypeCompare) (int, error) {
+	if lo >= hi {
+		return 0, fmt.Errorf("%w: lo < hi", ErrValueTypeInternalInvariant)
+	}
+	runHi := lo + 1
+	if runHi == hi {
+		return 1, nil
+	}
+	if compare(a[runHi], a[lo]) < 0 {
+		runHi++
+		for runHi < hi && compare(a[runHi], a[runHi-1]) < 0 {
+			runHi++
+		}
+		reverseRange(a, lo, runHi)
+	} else {
+		for runHi < hi && compare(a[runHi], a[runHi-1]) >= 0 {
+			runHi++
+		}
+	}
+	return runHi - lo, nil
+}
+
+func reverseRange(a []ValueType, lo, hi int) {
+	hi--
+	for lo < hi {
+		a[lo], a[hi] = a[hi], a[lo]
+		lo++
+		hi--
+	}
+}
+
+func minRunLength(n int) (int, error) {
+	const minMerge = 32
+	if n < 0 {
+		return 0, fmt.Errorf("%w: n >= 0", ErrValueTypeInternalInvariant)
+	}
+	r := 0 // Becomes 1 if any 1 bits are shifted off
+	for n >= minMerge {
+		r |= (n & 1)
+		n >>= 1
+	}
+	return n + r, nil
+}
+
+func (h *timSortHandler) pushRun(runBase, runLen int) {
+	h.runBase[h.stackSize] = runBase
+	h.runLen[h.stackSize] = runLen
+	h.stackSize++
+}
+
+func (h *timSortHandler) mergeCollapse() (err error) {
+	for h.stackSize > 1 {
+		n := h.stackSize - 2
+		if (n > 0 && h.runLen[n-1] <= h.runLen[n]+h.runLen[n+1]) ||
+			(n > 1 && h.runLen[n-2] <= h.runLen[n-1]+h.runLen[n]) {
+			if h.runLen[n-1] < h.runLen[n+1] {
+				n--
+			}
+			if err = h.mergeAt(n); err != nil {
+				return
+			}
+		} else if h.runLen[n] <= h.runLen[n+1] {
+			if err = h.mergeAt(n); err != nil {
+				return
+			}
+		} else {
+			break
+		}
+	}
+	return
+}
+
+func (h *timSortHandler) mergeForceCollapse() (err error) {
+	for h.stackSize > 1 {
+		n := h.stackSize - 2
+		if n > 0 && h.runLen[n-1] < h.runLen[n+1] {
+			n--
+		}
+		if err = h.mergeAt(n); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (h *timSortHandler) mergeAt(i int) (err error) {
+	if h.stackSize < 2 {
+		return fmt.Errorf("%w: stackSize >= 2", ErrValueTypeInternalInvariant)
+	}
+	if i < 0 {
+		return fmt.Errorf("%w: i >= 0", ErrValueTypeInternalInvariant)
+	}
+	if i != h.stackSize-2 && i != h.stackSize-3 {
+		return fmt.Errorf("%w: if i == stackSize - 2 || i == stackSize - 3", ErrValueTypeInternalInvariant)
+	}
+	base1 := h.runBase[i]
+	len1 := h.runLen[i]
+	base2 := h.runBase[i+1]
+	len2 := h.runLen[i+1]
+	if len1 <= 0 || len2 <= 0 {
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0", ErrValueTypeInternalInvariant)
+	}
+	if base1+len1 != base2 {
+		return fmt.Errorf("%w: base1 + len1 == base2", ErrValueTypeInternalInvariant)
+	}
+	h.runLen[i] = len1 + len2
+	if i == h.stackSize-3 {
+		h.runBase[i+1] = h.runBase[i+2]
+		h.runLen[i+1] = h.runLen[i+2]
+	}
+	h.stackSize--
+	k, err := gallopRight(h.a[base2], h.a, base1, len1, 0, h.compare)
+	if err != nil {
+		return err
+	}
+	if k < 0 {
+		return fmt.Errorf("%w: k >= 0", ErrValueTypeInternalInvariant)
+	}
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+	len2, err = gallopLeft(h.a[base1+len1-1], h.a, base2, len2, len2-1, h.compare)
+	if err != nil {
+		return
+	}
+	if len2 < 0 {
+		return fmt.Errorf("%w: len2 >= 0", ErrValueTypeInternalInvariant)
+	}
+	if len2 == 0 {
+		return
+	}
+	if len1 <= len2 {
+		err = h.mergeLo(base1, len1, base2, len2)
+		if err != nil {
+			return fmt.Errorf("mergeLo: %w", err)
+		}
+	} else {
+		err = h.mergeHi(base1, len1, base2, len2)
+		if err != nil {
+			return fmt.Errorf("mergeHi: %w", err)
+		}
+	}
+	return
+}
+
+func gallopLeft(key ValueType, a []ValueType, base, len, hint int, compare ValueTypeCompare) (int, error) {
+	if len <= 0 || hint < 0 || hint >= len {
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrValueTypeInternalInvariant)
+	}
+	lastOfs := 0
+	ofs := 1
+
+	if compare(a[base+hint], key) < 0 {
+		maxOfs := len - hint
+		for ofs < maxOfs && compare(a[base+hint+ofs], key) < 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 {
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs += hint
+		ofs += hint
+	} else {
+		maxOfs := hint + 1
+		for ofs < maxOfs && compare(a[base+hint-ofs], key) >= 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 {
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		tmp := lastOfs
+		lastOfs = hint - ofs
+		ofs = hint - tmp
+	}
+	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrValueTypeInternalInvariant)
+	}
+	lastOfs++
+	for lastOfs < ofs {
+		m := lastOfs + (ofs-lastOfs)/2
+
+		if compare(a[base+m], key) < 0 {
+			lastOfs = m + 1
+		} else {
+			ofs = m
+		}
+	}
+	if lastOfs != ofs {
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrValueTypeInternalInvariant)
+	}
+	return ofs, nil
+}
+
+func gallopRight(key ValueType, a []ValueType, base, len, hint int, compare ValueTypeCompare) (int, error) {
+	if len <= 0 || hint < 0 || hint >= len {
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrValueTypeInternalInvariant)
+	}
+
+	ofs := 1
+	lastOfs := 0
+	if compare(key, a[base+hint]) < 0 {
+		maxOfs := hint + 1
+		for ofs < maxOfs && compare(key, a[base+hint-ofs]) < 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 { // int overflow
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		tmp := lastOfs
+		lastOfs = hint - ofs
+		ofs = hint - tmp
+	} else {
+		maxOfs := len - hint
+		for ofs < maxOfs && compare(key, a[base+hint+ofs]) >= 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 {
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs += hint
+		ofs += hint
+	}
+	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrValueTypeInternalInvariant)
+	}
+	lastOfs++
+	for lastOfs < ofs {
+		m := lastOfs + (ofs-lastOfs)/2
+
+		if compare(key, a[base+m]) < 0 {
+			ofs = m
+		} else {
+			lastOfs = m + 1
+		}
+	}
+	if lastOfs != ofs {
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrValueTypeInternalInvariant)
+	}
+	return ofs, nil
+}
+
+func (h *timSortHandler) mergeLo(base1, len1, base2, len2 int) (err error) {
+	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrValueTypeInternalInvariant)
+	}
+	a := h.a
+	tmp := h.ensureCapacity(len1)
+	copy(tmp, a[base1:base1+len1])
+	cursor1 := 0
+	cursor2 := base2
+	dest := base1
+	a[dest] = a[cursor2]
+	dest++
+	cursor2++
+	len2--
+	if len2 == 0 {
+		copy(a[dest:dest+len1], tmp)
+		return
+	}
+	if len1 == 1 {
+		copy(a[dest:dest+len2], a[cursor2:cursor2+len2])
+		a[dest+len2] = tmp[cursor1]
+		return
+	}
+	compare := h.compare
+	minGallop := h.minGallop
+outer:
+	for {
+		count1 := 0
+		count2 := 0
+		for {
+			if len1 <= 1 || len2 <= 0 {
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrValueTypeInternalInvariant)
+			}
+
+			if compare(a[cursor2], tmp[cursor1]) < 0 {
+				a[dest] = a[cursor2]
+				dest++
+				cursor2++
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 0 {
+					break outer
+				}
+			} else {
+				a[dest] = tmp[cursor1]
+				dest++
+				cursor1++
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 1 {
+					break outer
+				}
+			}
+			if (count1 | count2) >= minGallop {
+				break
+			}
+		}
+		for {
+			if len1 <= 1 || len2 <= 0 {
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrValueTypeInternalInvariant)
+			}
+			count1, err = gallopRight(a[cursor2], tmp, cursor1, len1, 0, compare)
+			if err != nil {
+				return
+			}
+			if count1 != 0 {
+				copy(a[dest:dest+count1], tmp[cursor1:cursor1+count1])
+				dest += count1
+				cursor1 += count1
+				len1 -= count1
+				if len1 <= 1 {
+					break outer
+				}
+			}
+			a[dest] = a[cursor2]
+			dest++
+			cursor2++
+			len2--
+			if len2 == 0 {
+				break outer
+			}
+			count2, err = gallopLeft(tmp[cursor1], a, cursor2, len2, 0, compare)
+			if err != nil {
+				return
+			}
+			if count2 != 0 {
+				copy(a[dest:dest+count2], a[cursor2:cursor2+count2])
+				dest += count2
+				cursor2 += count2
+				len2 -= count2
+				if len2 == 0 {
+					break outer
+				}
+			}
+			a[dest] = tmp[cursor1]
+			dest++
+			cursor1++
+			len1--
+			if len1 == 1 {
+				break outer
+			}
+			minGallop--
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if minGallop < 0 {
+			minGallop = 0
+		}
+		minGallop += 2
+	}
+	if minGallop < 1 {
+		minGallop = 1
+	}
+	h.minGallop = minGallop
+	if len1 == 1 {
+
+		if len2 <= 0 {
+			return fmt.Errorf("%w: len2 > 0", ErrValueTypeInternalInvariant)
+		}
+		copy(a[dest:dest+len2], a[cursor2:cursor2+len2])
+		a[dest+len2] = tmp[cursor1]
+	} else if len1 == 0 {
+		return ErrValueTypeComparatorContract
+	} else {
+		if len2 != 0 {
+			return fmt.Errorf("%w: len2 == 0", ErrValueTypeInternalInvariant)
+		}
+		if len1 <= 1 {
+			return fmt.Errorf("%w: len1 > 1", ErrValueTypeInternalInvariant)
+		}
+		copy(a[dest:dest+len1], tmp[cursor1:cursor1+len1])
+	}
+	return
+}
+
+func (h *timSortHandler) mergeHi(base1, len1, base2, len2 int) (err error) {
+	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrValueTypeInternalInvariant)
+	}
+	a := h.a
+	tmp := h.ensureCapacity(len2)
+	copy(tmp, a[base2:base2+len2])
+	cursor1 := base1 + len1 - 1
+	cursor2 := len2 - 1
+	dest := base2 + len2 - 1
+	a[dest] = a[cursor1]
+	dest--
+	cursor1--
+	len1--
+	if len1 == 0 {
+		dest -= len2 - 1
+		copy(a[dest:dest+len2], tmp)
+		return
+	}
+	if len2 == 1 {
+		dest -= len1 - 1
+		cursor1 -= len1 - 1
+		copy(a[dest:dest+len1], a[cursor1:cursor1+len1])
+		a[dest-1] = tmp[cursor2]
+		return
+	}
+	compare := h.compare
+	minGallop := h.minGallop
+outer:
+	for {
+		count1 := 0 // Number of times in a row that first run won
+		count2 := 0 // Number of times in a row that second run won
+		for {
+			if len1 <= 0 || len2 <= 1 {
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrValueTypeInternalInvariant)
+			}
+			if compare(tmp[cursor2], a[cursor1]) < 0 {
+				a[dest] = a[cursor1]
+				dest--
+				cursor1--
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 0 {
+					break outer
+				}
+			} else {
+				a[dest] = tmp[cursor2]
+				dest--
+				cursor2--
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 1 {
+					break outer
+				}
+			}
+			if (count1 | count2) >= minGallop {
+				break
+			}
+		}
+		for {
+			if len1 <= 0 || len2 <= 1 {
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrValueTypeInternalInvariant)
+			}
+			if gr, err := gallopRight(tmp[cursor2], a, base1, len1, len1-1, compare); err == nil {
+				count1 = len1 - gr
+			} else {
+				return err
+			}
+			if count1 != 0 {
+				dest -= count1
+				cursor1 -= count1
+				len1 -= count1
+				copy(a[dest+1:dest+1+count1], a[cursor1+1:cursor1+1+count1])
+				if len1 == 0 {
+					break outer
+				}
+			}
+			a[dest] = tmp[cursor2]
+			dest--
+			cursor2--
+			len2--
+			if len2 == 1 {
+				break outer
+			}
+
+			if gl, err := gallopLeft(a[cursor1], tmp, 0, len2, len2-1, compare); err == nil {
+				count2 = len2 - gl
+			} else {
+				return err
+			}
+			if count2 != 0 {
+				dest -= count2
+				cursor2 -= count2
+				len2 -= count2
+				copy(a[dest+1:dest+1+count2], tmp[cursor2+1:cursor2+1+count2])
+				if len2 <= 1 { // len2 == 1 || len2 == 0
+					break outer
+				}
+			}
+			a[dest] = a[cursor1]
+			dest--
+			cursor1--
+			len1--
+			if len1 == 0 {
+				break outer
+			}
+			minGallop--
+
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if minGallop < 0 {
+			minGallop = 0
+		}
+		minGallop += 2 // Penalize for leaving gallop mode
+	} // End of "outer" loop
+
+	if minGallop < 1 {
+		minGallop = 1
+	}
+
+	h.minGallop = minGallop // Write back to field
+
+	if len2 == 1 {
+		if len1 <= 0 {
+			return fmt.Errorf("%w: len1 > 0", ErrValueTypeInternalInvariant)
+		}
+		dest -= len1
+		cursor1 -= len1
+
+		copy(a[dest+1:dest+1+len1], a[cursor1+1:cursor1+1+len1])
+		a[dest] = tmp[cursor2] // Move first elt of run2 to front of merge
+	} else if len2 == 0 {
+		return ErrValueTypeComparatorContract
+	} else {
+		if len1 != 0 {
+			return fmt.Errorf("%w: len1 == 0", ErrValueTypeInternalInvariant)
+		}
+
+		if len2 <= 0 {
+			return fmt.Errorf("%w: len2 > 0", ErrValueTypeInternalInvariant)
+		}
+
+		copy(a[dest-(len2-1):dest+1], tmp)
+	}
+	return
+}
+
+func (h *timSortHandler) ensureCapacity(minCapacity int) []ValueType {
+	if len(h.tmp) < minCapacity {
+		// Compute smallest power of 2 > minCapacity
+		newSize := minCapacity
+		newSize |= newSize >> 1
+		newSize |= newSize >> 2
+		newSize |= newSize >> 4
+		newSize |= newSize >> 8
+		newSize |= newSize >> 16
+		newSize++
+
+		if newSize < 0 { // Not bloody likely!
+			newSize = minCapacity
+		} else {
+			ns := len(h.a) / 2
+			if ns < newSize {
+				newSize = ns
+			}
+		}
+
+		h.tmp = make([]ValueType, newSize)
+	}
+
+	return h.tmp
+}
+
+// ValueTypeBinarySearch returns first index i that satisfies slices[i] <= item.
+func ValueTypeBinarySearch(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)-1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if compare(sorted[h], item) < 0 {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// ValueTypeLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func ValueTypeLowerBound(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if compare(sorted[h], item) < 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// ValueTypeUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func ValueTypeUpperBound(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if compare(sorted[h], item) <= 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopValueType returns first index i that satisfies !(sorted[i] < item) like ValueTypeLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopValueType(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	if len(sorted) == 0 || compare(sorted[0], item) >= 0 {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && compare(sorted[hi], item) < 0 {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + ValueTypeLowerBound(sorted[lo+1:hi], item, compare)
+}
+
+// ValueTypeEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func ValueTypeEqualRange(sorted []ValueType, item ValueType, compare ValueTypeCompare) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, item, compare)
+	hi = lo + ValueTypeUpperBound(sorted[lo:], item, compare)
+	return lo, hi
+}
+
+// ValueTypeCount returns the number of items that are equal to item.
+func ValueTypeCount(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	lo, hi := ValueTypeEqualRange(sorted, item, compare)
+	return hi - lo
+}
+
+// ValueTypeFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeFloor(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item, compare)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeCeiling(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item, compare)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeLower(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item, compare)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeHigher(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item, compare)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func ValueTypeRangeIndexes(sorted []ValueType, from, to ValueType, inclusive bool, compare ValueTypeCompare) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, from, compare)
+	if inclusive {
+		hi = lo + ValueTypeUpperBound(sorted[lo:], to, compare)
+	} else {
+		hi = lo + ValueTypeLowerBound(sorted[lo:], to, compare)
+	}
+	return lo, hi
+}
+
+// ValueTypeRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func ValueTypeRange(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, compare)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like ValueTypeRange.
+func ValueTypeRangeInclusive(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, compare)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRemoveRange removes items in [from, to) and returns a sorted slice.
+func ValueTypeRemoveRange(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, compare)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func ValueTypeRemoveRangeInclusive(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, compare)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeCountRange returns the number of items in [from, to).
+func ValueTypeCountRange(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, compare)
+	return hi - lo
+}
+
+// ValueTypeCountRangeInclusive returns the number of items in [from, to].
+func ValueTypeCountRangeInclusive(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, compare)
+	return hi - lo
+}
+
+// ValueTypeIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
+func ValueTypeIndexOf(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	if len(sorted) == 0 {
+		return -1
+	}
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	if compare(sorted[i], item) == 0 {
+		return i
+	}
+	return -1
+}
+
+// ValueTypeContains returns true if item is in a sorted slice. Otherwise false.
+func ValueTypeContains(sorted []ValueType, item ValueType, compare ValueTypeCompare) bool {
+	if len(sorted) == 0 {
+		return false
+	}
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	return compare(sorted[i], item) == 0
+}
+
+// ValueTypeInsert inserts item in correct position and returns a sorted slice.
+func ValueTypeInsert(sorted []ValueType, item ValueType, compare ValueTypeCompare) []ValueType {
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	if i == len(sorted)-1 && compare(sorted[i], item) < 0 {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...)
+}
+
+// ValueTypeInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling ValueTypeInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func ValueTypeInsertAll(sorted []ValueType, items []ValueType, compare ValueTypeCompare) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]ValueType, len(items))
+	copy(batch, items)
+	ValueTypeSort(batch, compare)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && compare(batch[j], sorted[i]) < 0 {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]ValueType, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if compare(batch[j], sorted[i]) < 0 {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// ValueTypeInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func ValueTypeInsertUnique(sorted []ValueType, item ValueType, compare ValueTypeCompare) ([]ValueType, bool) {
+	i := ValueTypeLowerBound(sorted, item, compare)
+	if i < len(sorted) && compare(sorted[i], item) == 0 {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...), true
+}
+
+// ValueTypeRemove removes item in a sorted slice.
+func ValueTypeRemove(sorted []ValueType, item ValueType, compare ValueTypeCompare) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	if compare(sorted[i], item) == 0 {
+		return ValueTypeRemoveAt(sorted, i)
+	}
+	return sorted
+}
+
+// ValueTypeRemoveAt removes item in a slice.
+func ValueTypeRemoveAt(sorted []ValueType, i int) []ValueType {
+	return append(sorted[:i], sorted[i+1:]...)
+}
+
+// ValueTypeRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveAll(sorted []ValueType, items []ValueType, compare ValueTypeCompare) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && compare(items[j], item) < 0 {
+			j++
+		}
+		if j < len(items) && compare(items[j], item) == 0 {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveIf(sorted []ValueType, pred func(item ValueType) bool) []ValueType {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func ValueTypeRemoveIndexes(sorted []ValueType, indexes []int) []ValueType {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func ValueTypeUnique(sorted []ValueType, compare ValueTypeCompare) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if compare(result[len(result)-1], item) < 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func ValueTypeUniqueFunc(sorted []ValueType, equal func(a, b ValueType) bool) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdValueType is the number of source slices that ValueTypeIterateOver and ValueTypeUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdValueType = 8
+
+// ValueTypeIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
+func ValueTypeIterateOver(compare ValueTypeCompare, callback func(item ValueType, srcIndex int), sorted ...[]ValueType) {
+	sourceSlices := make([][]ValueType, 0, len(sorted))
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
+		if len(src) > 0 {
+			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
+		}
+	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		iterateOverHeapValueType(compare, callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearValueType(compare, callback, sourceSlices, sliceIndex)
+	}
+}
+
+// iterateOverLinearValueType finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapValueType when there are a few source slices.
+func iterateOverLinearValueType(compare ValueTypeCompare, callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
+	}
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < len(sourceSlices); i++ {
+			if compare(sourceSlices[i][indexes[i]], minItem) < 0 {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		callback(minItem, sliceIndex[minSlice])
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapValueType keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearValueType takes O(N k).
+func iterateOverHeapValueType(compare ValueTypeCompare, callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if c := compare(x, y); c != 0 {
+			return c < 0
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
+		}
+		down(0)
+	}
+}
+
+// ValueTypeUnion unions sorted slices and returns new slices.
+func ValueTypeUnion(compare ValueTypeCompare, sorted ...[]ValueType) []ValueType {
+	length := 0
+	sourceSlices := make([][]ValueType, 0, len(sorted))
+	for _, src := range sorted {
+		if len(src) > 0 {
+			length += len(src)
+			sourceSlices = append(sourceSlices, src)
+		}
+	}
+	if length == 0 {
+		return nil
+	} else if len(sourceSlices) == 1 {
+		return sourceSlices[0]
+	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		result := make([]ValueType, 0, length)
+		ValueTypeIterateOver(compare, func(item ValueType, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
+	result := make([]ValueType, length)
+	sourceSliceCount := len(sourceSlices)
+	indexes := make([]int, sourceSliceCount)
+	index := 0
+	for {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < sourceSliceCount; i++ {
+			if compare(sourceSlices[i][indexes[i]], minItem) < 0 {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		result[index] = minItem
+		index++
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sourceSliceCount--
+			if len(sourceSlices) == 1 {
+				copy(result[index:], sourceSlices[0][indexes[0]:])
+				return result
+			}
+		}
+	}
+}
+
+// ValueTypeUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func ValueTypeUnionDistinct(compare ValueTypeCompare, sorted ...[]ValueType) []ValueType {
+	var result []ValueType
+	ValueTypeIterateOver(compare, func(item ValueType, srcIndex int) {
+		if len(result) == 0 || compare(result[len(result)-1], item) < 0 {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// ValueTypeDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
+func ValueTypeDifference(compare ValueTypeCompare, sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopValueType(sorted1[i:], sorted2[j], compare)
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i], compare)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if compare(sorted1[i], sorted2[j]) == 0 {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// ValueTypeSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func ValueTypeSymmetricDifference(compare ValueTypeCompare, sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		c := compare(sorted1[i], sorted2[j])
+		switch {
+		case c < 0:
+			result = append(result, sorted1[i])
+			i++
+		case c > 0:
+			result = append(result, sorted2[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
+	return result
+}
+
+// ValueTypeDifferenceAll creates difference group of base and all subtract slices and returns.
+func ValueTypeDifferenceAll(compare ValueTypeCompare, base []ValueType, subtract ...[]ValueType) []ValueType {
+	return ValueTypeDifference(compare, base, ValueTypeUnion(compare, subtract...))
+}
+
+// ValueTypeDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func ValueTypeDiff(compare ValueTypeCompare, old, new []ValueType) (added, removed, common []ValueType) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		c := compare(old[i], new[j])
+		switch {
+		case c < 0:
+			removed = append(removed, old[i])
+			i++
+		case c > 0:
+			added = append(added, new[j])
+			j++
+		default:
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// ValueTypeIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func ValueTypeIsSubset(compare ValueTypeCompare, sub, super []ValueType) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopValueType(super[j:], item, compare)
+		if j == len(super) || compare(item, super[j]) < 0 {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// ValueTypeIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func ValueTypeIsSuperset(compare ValueTypeCompare, super, sub []ValueType) bool {
+	return ValueTypeIsSubset(compare, sub, super)
+}
+
+// ValueTypeIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func ValueTypeIsDisjoint(compare ValueTypeCompare, sorted1, sorted2 []ValueType) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopValueType(sorted1[i:], sorted2[j], compare)
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i], compare)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if compare(sorted1[i], sorted2[j]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func ValueTypeEqual(compare ValueTypeCompare, sorted1, sorted2 []ValueType) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if compare(sorted1[i], sorted2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeIntersectionSize returns the number of items that ValueTypeIntersection returns for sorted1 and sorted2
+// without allocating memory. Like ValueTypeIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func ValueTypeIntersectionSize(compare ValueTypeCompare, sorted1, sorted2 []ValueType) int {
+	// ValueTypeIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopValueType(long[j:], item, compare)
+		if j == len(long) {
+			break
+		}
+		if compare(item, long[j]) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// ValueTypeIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
+func ValueTypeIntersection(compare ValueTypeCompare, sorted ...[]ValueType) []ValueType {
+	return ValueTypeIntersectionFunc(compare, nil, sorted...)
+}
+
+// ValueTypeIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func ValueTypeIntersectionFunc(compare ValueTypeCompare, callback func(item ValueType, indexes []int), sorted ...[]ValueType) []ValueType {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
+	})
+	if len(sorted) > heapMergeThresholdValueType {
+		return intersectionHeapValueType(compare, callback, sorted, order[0])
+	}
+	return intersectionLinearValueType(compare, callback, sorted, order)
+}
+
+// intersectionLinearValueType checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapValueType when there are a few source slices or the items are spread evenly.
+func intersectionLinearValueType(compare ValueTypeCompare, callback func(item ValueType, indexes []int), sorted [][]ValueType, order []int) []ValueType {
+	var result []ValueType
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopValueType(src[cursors[i]:], value, compare)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if compare(value, src[cursors[i]]) < 0 {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapValueType keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearValueType when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapValueType(compare ValueTypeCompare, callback func(item ValueType, indexes []int), sorted [][]ValueType, shortest int) []ValueType {
+	var result []ValueType
+	if len(sorted[shortest]) == 0 {
+		return result
+	}
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if compare(maxItem, sorted[i][0]) < 0 {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return compare(sorted[a][cursors[a]], sorted[b][cursors[b]]) < 0
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if compare(sorted[src][cursors[src]], maxItem) < 0 {
+			cursors[src] += gallopValueType(sorted[src][cursors[src]:], maxItem, compare)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; compare(maxItem, item) < 0 {
+				maxItem = item
+			}
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && compare(maxItem, s[cursors[shortest]]) >= 0 {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
+			}
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
+				break
+			}
+		}
+	}
+}
+
+// ValueTypeSortedSet is a set that keeps unique items in a sorted slice.
+type ValueTypeSortedSet struct {
+	items   []ValueType
+	compare ValueTypeCompare
+}
+
+// NewValueTypeSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewValueTypeSortedSet(compare ValueTypeCompare, items ...ValueType) *ValueTypeSortedSet {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted, compare)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || compare(unique[len(unique)-1], item) != 0 {
+			unique = append(unique, item)
+		}
+	}
+	return &ValueTypeSortedSet{items: unique, compare: compare}
+}
+
+// search returns first index i that satisfies item <= s.items[i] and the result of comparison of them.
+// If there is no such item, it returns s.Len() and -1.
+func (s *ValueTypeSortedSet) search(item ValueType) (int, int) {
+	i, j := 0, len(s.items)
+	c := -1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if hc := s.compare(item, s.items[h]); hc > 0 {
+			i = h + 1
+		} else {
+			j = h
+			c = hc
+		}
+	}
+	return i, c
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *ValueTypeSortedSet) Add(item ValueType) bool {
+	i, c := s.search(item)
+	if c == 0 {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *ValueTypeSortedSet) Delete(item ValueType) bool {
+	i, c := s.search(item)
+	if c != 0 {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *ValueTypeSortedSet) Has(item ValueType) bool {
+	_, c := s.search(item)
+	return c == 0
+}
+
+// Len returns the number of items in the set.
+func (s *ValueTypeSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *ValueTypeSortedSet) At(i int) ValueType {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *ValueTypeSortedSet) Range(callback func(i int, item ValueType) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *ValueTypeSortedSet) Slice() []ValueType {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *ValueTypeSortedSet) Union(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	result := make([]ValueType, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, s.items[i])
+			i++
+		} else if c > 0 {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedSet{items: result, compare: s.compare}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *ValueTypeSortedSet) Intersect(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedSet{items: result, compare: s.compare}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *ValueTypeSortedSet) Subtract(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, s.items[i])
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &ValueTypeSortedSet{items: result, compare: s.compare}
+}
+
+// ValueTypeSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type ValueTypeSortedMultiset struct {
+	items   []ValueType
+	compare ValueTypeCompare
+}
+
+// NewValueTypeSortedMultiset creates a sorted multiset that has items.
+func NewValueTypeSortedMultiset(compare ValueTypeCompare, items ...ValueType) *ValueTypeSortedMultiset {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted, compare)
+	return &ValueTypeSortedMultiset{items: sorted, compare: compare}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *ValueTypeSortedMultiset) equalRange(item ValueType) (lo, hi int) {
+	return ValueTypeEqualRange(m.items, item, m.compare)
+}
+
+// Add adds item to the multiset.
+func (m *ValueTypeSortedMultiset) Add(item ValueType) {
+	m.items = ValueTypeInsert(m.items, item, m.compare)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *ValueTypeSortedMultiset) RemoveOne(item ValueType) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = ValueTypeRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *ValueTypeSortedMultiset) RemoveAll(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *ValueTypeSortedMultiset) Count(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *ValueTypeSortedMultiset) Has(item ValueType) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *ValueTypeSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *ValueTypeSortedMultiset) Distinct() []ValueType {
+	var result []ValueType
+	for i, item := range m.items {
+		if i == 0 || m.compare(result[len(result)-1], item) != 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *ValueTypeSortedMultiset) Range(callback func(item ValueType, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && m.compare(m.items[lo], m.items[hi]) == 0 {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *ValueTypeSortedMultiset) Slice() []ValueType {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *ValueTypeSortedMultiset) Union(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	result := make([]ValueType, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, m.items[i])
+			i++
+		} else if c > 0 {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedMultiset{items: result, compare: m.compare}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *ValueTypeSortedMultiset) Intersect(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedMultiset{items: result, compare: m.compare}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *ValueTypeSortedMultiset) Subtract(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, m.items[i])
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &ValueTypeSortedMultiset{items: result, compare: m.compare}
+}
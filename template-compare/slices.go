@@ -0,0 +1,1176 @@
+package template_compare
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cheekybits/genny/generic"
+	"sort"
+)
+
+type ValueType generic.Type
+
+// ValueTypeCompare is Delegate type that sorting uses as a comparator.
+// It returns a negative number when a < b, a positive number when a > b and zero when a == b.
+type ValueTypeCompare func(a, b ValueType) int
+
+// ErrValueTypeComparatorContract is returned when a comparator doesn't satisfy the contract of strict weak ordering
+// (for example, compare(a, a) != 0 or the sort result is not in order). Use errors.Is to check it.
+var ErrValueTypeComparatorContract = errors.New("comparison method violates its general contract")
+
+// ValueTypeSort sorts an array using the provided comparator.
+// It uses sort.Slice, so it is not stable. slicesgen -stable generates it with sort.SliceStable.
+func ValueTypeSort(a []ValueType, compare ValueTypeCompare) (err error) {
+	sort.Slice(a, func(i, j int) bool {
+		return compare(a[i], a[j]) < 0
+	})
+	for i := 1; i < len(a); i++ {
+		if compare(a[i], a[i-1]) < 0 {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// ValueTypeValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If ValueTypeSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrValueTypeComparatorContract.
+func ValueTypeValidate(sorted []ValueType, compare ValueTypeCompare) error {
+	for i, item := range sorted {
+		if compare(item, item) != 0 {
+			return fmt.Errorf("%w: item is not equal to itself at %d", ErrValueTypeComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if compare(item, prev) < 0 {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrValueTypeComparatorContract, i-1, i)
+		}
+		if c1, c2 := compare(prev, item), compare(item, prev); (c1 < 0) != (c2 > 0) || (c1 == 0) != (c2 == 0) {
+			return fmt.Errorf("%w: comparison results of items at %d and %d are not antisymmetric", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// ValueTypeBinarySearch returns first index i that satisfies slices[i] <= item.
+func ValueTypeBinarySearch(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)-1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if compare(sorted[h], item) < 0 {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// ValueTypeLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func ValueTypeLowerBound(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if compare(sorted[h], item) < 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// ValueTypeUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func ValueTypeUpperBound(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if compare(sorted[h], item) <= 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopValueType returns first index i that satisfies !(sorted[i] < item) like ValueTypeLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopValueType(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	if len(sorted) == 0 || compare(sorted[0], item) >= 0 {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && compare(sorted[hi], item) < 0 {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + ValueTypeLowerBound(sorted[lo+1:hi], item, compare)
+}
+
+// ValueTypeEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func ValueTypeEqualRange(sorted []ValueType, item ValueType, compare ValueTypeCompare) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, item, compare)
+	hi = lo + ValueTypeUpperBound(sorted[lo:], item, compare)
+	return lo, hi
+}
+
+// ValueTypeCount returns the number of items that are equal to item.
+func ValueTypeCount(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	lo, hi := ValueTypeEqualRange(sorted, item, compare)
+	return hi - lo
+}
+
+// ValueTypeFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeFloor(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item, compare)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeCeiling(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item, compare)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeLower(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item, compare)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeHigher(sorted []ValueType, item ValueType, compare ValueTypeCompare) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item, compare)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func ValueTypeRangeIndexes(sorted []ValueType, from, to ValueType, inclusive bool, compare ValueTypeCompare) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, from, compare)
+	if inclusive {
+		hi = lo + ValueTypeUpperBound(sorted[lo:], to, compare)
+	} else {
+		hi = lo + ValueTypeLowerBound(sorted[lo:], to, compare)
+	}
+	return lo, hi
+}
+
+// ValueTypeRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func ValueTypeRange(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, compare)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like ValueTypeRange.
+func ValueTypeRangeInclusive(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, compare)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRemoveRange removes items in [from, to) and returns a sorted slice.
+func ValueTypeRemoveRange(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, compare)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func ValueTypeRemoveRangeInclusive(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, compare)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeCountRange returns the number of items in [from, to).
+func ValueTypeCountRange(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, compare)
+	return hi - lo
+}
+
+// ValueTypeCountRangeInclusive returns the number of items in [from, to].
+func ValueTypeCountRangeInclusive(sorted []ValueType, from, to ValueType, compare ValueTypeCompare) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, compare)
+	return hi - lo
+}
+
+// ValueTypeIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
+func ValueTypeIndexOf(sorted []ValueType, item ValueType, compare ValueTypeCompare) int {
+	if len(sorted) == 0 {
+		return -1
+	}
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	if compare(sorted[i], item) == 0 {
+		return i
+	}
+	return -1
+}
+
+// ValueTypeContains returns true if item is in a sorted slice. Otherwise false.
+func ValueTypeContains(sorted []ValueType, item ValueType, compare ValueTypeCompare) bool {
+	if len(sorted) == 0 {
+		return false
+	}
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	return compare(sorted[i], item) == 0
+}
+
+// ValueTypeInsert inserts item in correct position and returns a sorted slice.
+func ValueTypeInsert(sorted []ValueType, item ValueType, compare ValueTypeCompare) []ValueType {
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	if i == len(sorted)-1 && compare(sorted[i], item) < 0 {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...)
+}
+
+// ValueTypeInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling ValueTypeInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func ValueTypeInsertAll(sorted []ValueType, items []ValueType, compare ValueTypeCompare) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]ValueType, len(items))
+	copy(batch, items)
+	ValueTypeSort(batch, compare)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && compare(batch[j], sorted[i]) < 0 {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]ValueType, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if compare(batch[j], sorted[i]) < 0 {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// ValueTypeInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func ValueTypeInsertUnique(sorted []ValueType, item ValueType, compare ValueTypeCompare) ([]ValueType, bool) {
+	i := ValueTypeLowerBound(sorted, item, compare)
+	if i < len(sorted) && compare(sorted[i], item) == 0 {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...), true
+}
+
+// ValueTypeRemove removes item in a sorted slice.
+func ValueTypeRemove(sorted []ValueType, item ValueType, compare ValueTypeCompare) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	i := ValueTypeBinarySearch(sorted, item, compare)
+	if compare(sorted[i], item) == 0 {
+		return ValueTypeRemoveAt(sorted, i)
+	}
+	return sorted
+}
+
+// ValueTypeRemoveAt removes item in a slice.
+func ValueTypeRemoveAt(sorted []ValueType, i int) []ValueType {
+	return append(sorted[:i], sorted[i+1:]...)
+}
+
+// ValueTypeRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveAll(sorted []ValueType, items []ValueType, compare ValueTypeCompare) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && compare(items[j], item) < 0 {
+			j++
+		}
+		if j < len(items) && compare(items[j], item) == 0 {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveIf(sorted []ValueType, pred func(item ValueType) bool) []ValueType {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func ValueTypeRemoveIndexes(sorted []ValueType, indexes []int) []ValueType {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func ValueTypeUnique(sorted []ValueType, compare ValueTypeCompare) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if compare(result[len(result)-1], item) < 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func ValueTypeUniqueFunc(sorted []ValueType, equal func(a, b ValueType) bool) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdValueType is the number of source slices that ValueTypeIterateOver and ValueTypeUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdValueType = 8
+
+// ValueTypeIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
+func ValueTypeIterateOver(compare ValueTypeCompare, callback func(item ValueType, srcIndex int), sorted ...[]ValueType) {
+	sourceSlices := make([][]ValueType, 0, len(sorted))
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
+		if len(src) > 0 {
+			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
+		}
+	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		iterateOverHeapValueType(compare, callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearValueType(compare, callback, sourceSlices, sliceIndex)
+	}
+}
+
+// iterateOverLinearValueType finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapValueType when there are a few source slices.
+func iterateOverLinearValueType(compare ValueTypeCompare, callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
+	}
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < len(sourceSlices); i++ {
+			if compare(sourceSlices[i][indexes[i]], minItem) < 0 {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		callback(minItem, sliceIndex[minSlice])
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapValueType keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearValueType takes O(N k).
+func iterateOverHeapValueType(compare ValueTypeCompare, callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if c := compare(x, y); c != 0 {
+			return c < 0
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
+		}
+		down(0)
+	}
+}
+
+// ValueTypeUnion unions sorted slices and returns new slices.
+func ValueTypeUnion(compare ValueTypeCompare, sorted ...[]ValueType) []ValueType {
+	length := 0
+	sourceSlices := make([][]ValueType, 0, len(sorted))
+	for _, src := range sorted {
+		if len(src) > 0 {
+			length += len(src)
+			sourceSlices = append(sourceSlices, src)
+		}
+	}
+	if length == 0 {
+		return nil
+	} else if len(sourceSlices) == 1 {
+		return sourceSlices[0]
+	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		result := make([]ValueType, 0, length)
+		ValueTypeIterateOver(compare, func(item ValueType, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
+	result := make([]ValueType, length)
+	sourceSliceCount := len(sourceSlices)
+	indexes := make([]int, sourceSliceCount)
+	index := 0
+	for {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < sourceSliceCount; i++ {
+			if compare(sourceSlices[i][indexes[i]], minItem) < 0 {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		result[index] = minItem
+		index++
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sourceSliceCount--
+			if len(sourceSlices) == 1 {
+				copy(result[index:], sourceSlices[0][indexes[0]:])
+				return result
+			}
+		}
+	}
+}
+
+// ValueTypeUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func ValueTypeUnionDistinct(compare ValueTypeCompare, sorted ...[]ValueType) []ValueType {
+	var result []ValueType
+	ValueTypeIterateOver(compare, func(item ValueType, srcIndex int) {
+		if len(result) == 0 || compare(result[len(result)-1], item) < 0 {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// ValueTypeDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
+func ValueTypeDifference(compare ValueTypeCompare, sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopValueType(sorted1[i:], sorted2[j], compare)
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i], compare)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if compare(sorted1[i], sorted2[j]) == 0 {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// ValueTypeSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func ValueTypeSymmetricDifference(compare ValueTypeCompare, sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		c := compare(sorted1[i], sorted2[j])
+		switch {
+		case c < 0:
+			result = append(result, sorted1[i])
+			i++
+		case c > 0:
+			result = append(result, sorted2[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
+	return result
+}
+
+// ValueTypeDifferenceAll creates difference group of base and all subtract slices and returns.
+func ValueTypeDifferenceAll(compare ValueTypeCompare, base []ValueType, subtract ...[]ValueType) []ValueType {
+	return ValueTypeDifference(compare, base, ValueTypeUnion(compare, subtract...))
+}
+
+// ValueTypeDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func ValueTypeDiff(compare ValueTypeCompare, old, new []ValueType) (added, removed, common []ValueType) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		c := compare(old[i], new[j])
+		switch {
+		case c < 0:
+			removed = append(removed, old[i])
+			i++
+		case c > 0:
+			added = append(added, new[j])
+			j++
+		default:
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// ValueTypeIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func ValueTypeIsSubset(compare ValueTypeCompare, sub, super []ValueType) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopValueType(super[j:], item, compare)
+		if j == len(super) || compare(item, super[j]) < 0 {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// ValueTypeIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func ValueTypeIsSuperset(compare ValueTypeCompare, super, sub []ValueType) bool {
+	return ValueTypeIsSubset(compare, sub, super)
+}
+
+// ValueTypeIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func ValueTypeIsDisjoint(compare ValueTypeCompare, sorted1, sorted2 []ValueType) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopValueType(sorted1[i:], sorted2[j], compare)
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i], compare)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if compare(sorted1[i], sorted2[j]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func ValueTypeEqual(compare ValueTypeCompare, sorted1, sorted2 []ValueType) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if compare(sorted1[i], sorted2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeIntersectionSize returns the number of items that ValueTypeIntersection returns for sorted1 and sorted2
+// without allocating memory. Like ValueTypeIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func ValueTypeIntersectionSize(compare ValueTypeCompare, sorted1, sorted2 []ValueType) int {
+	// ValueTypeIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopValueType(long[j:], item, compare)
+		if j == len(long) {
+			break
+		}
+		if compare(item, long[j]) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// ValueTypeIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
+func ValueTypeIntersection(compare ValueTypeCompare, sorted ...[]ValueType) []ValueType {
+	return ValueTypeIntersectionFunc(compare, nil, sorted...)
+}
+
+// ValueTypeIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func ValueTypeIntersectionFunc(compare ValueTypeCompare, callback func(item ValueType, indexes []int), sorted ...[]ValueType) []ValueType {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
+	})
+	if len(sorted) > heapMergeThresholdValueType {
+		return intersectionHeapValueType(compare, callback, sorted, order[0])
+	}
+	return intersectionLinearValueType(compare, callback, sorted, order)
+}
+
+// intersectionLinearValueType checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapValueType when there are a few source slices or the items are spread evenly.
+func intersectionLinearValueType(compare ValueTypeCompare, callback func(item ValueType, indexes []int), sorted [][]ValueType, order []int) []ValueType {
+	var result []ValueType
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopValueType(src[cursors[i]:], value, compare)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if compare(value, src[cursors[i]]) < 0 {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapValueType keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearValueType when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapValueType(compare ValueTypeCompare, callback func(item ValueType, indexes []int), sorted [][]ValueType, shortest int) []ValueType {
+	var result []ValueType
+	if len(sorted[shortest]) == 0 {
+		return result
+	}
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if compare(maxItem, sorted[i][0]) < 0 {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return compare(sorted[a][cursors[a]], sorted[b][cursors[b]]) < 0
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if compare(sorted[src][cursors[src]], maxItem) < 0 {
+			cursors[src] += gallopValueType(sorted[src][cursors[src]:], maxItem, compare)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; compare(maxItem, item) < 0 {
+				maxItem = item
+			}
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && compare(maxItem, s[cursors[shortest]]) >= 0 {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
+			}
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
+				break
+			}
+		}
+	}
+}
+
+// ValueTypeSortedSet is a set that keeps unique items in a sorted slice.
+type ValueTypeSortedSet struct {
+	items   []ValueType
+	compare ValueTypeCompare
+}
+
+// NewValueTypeSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewValueTypeSortedSet(compare ValueTypeCompare, items ...ValueType) *ValueTypeSortedSet {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted, compare)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || compare(unique[len(unique)-1], item) != 0 {
+			unique = append(unique, item)
+		}
+	}
+	return &ValueTypeSortedSet{items: unique, compare: compare}
+}
+
+// search returns first index i that satisfies item <= s.items[i] and the result of comparison of them.
+// If there is no such item, it returns s.Len() and -1.
+func (s *ValueTypeSortedSet) search(item ValueType) (int, int) {
+	i, j := 0, len(s.items)
+	c := -1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if hc := s.compare(item, s.items[h]); hc > 0 {
+			i = h + 1
+		} else {
+			j = h
+			c = hc
+		}
+	}
+	return i, c
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *ValueTypeSortedSet) Add(item ValueType) bool {
+	i, c := s.search(item)
+	if c == 0 {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *ValueTypeSortedSet) Delete(item ValueType) bool {
+	i, c := s.search(item)
+	if c != 0 {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *ValueTypeSortedSet) Has(item ValueType) bool {
+	_, c := s.search(item)
+	return c == 0
+}
+
+// Len returns the number of items in the set.
+func (s *ValueTypeSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *ValueTypeSortedSet) At(i int) ValueType {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *ValueTypeSortedSet) Range(callback func(i int, item ValueType) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *ValueTypeSortedSet) Slice() []ValueType {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *ValueTypeSortedSet) Union(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	result := make([]ValueType, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, s.items[i])
+			i++
+		} else if c > 0 {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedSet{items: result, compare: s.compare}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *ValueTypeSortedSet) Intersect(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedSet{items: result, compare: s.compare}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *ValueTypeSortedSet) Subtract(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, s.items[i])
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &ValueTypeSortedSet{items: result, compare: s.compare}
+}
+
+// ValueTypeSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type ValueTypeSortedMultiset struct {
+	items   []ValueType
+	compare ValueTypeCompare
+}
+
+// NewValueTypeSortedMultiset creates a sorted multiset that has items.
+func NewValueTypeSortedMultiset(compare ValueTypeCompare, items ...ValueType) *ValueTypeSortedMultiset {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted, compare)
+	return &ValueTypeSortedMultiset{items: sorted, compare: compare}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *ValueTypeSortedMultiset) equalRange(item ValueType) (lo, hi int) {
+	return ValueTypeEqualRange(m.items, item, m.compare)
+}
+
+// Add adds item to the multiset.
+func (m *ValueTypeSortedMultiset) Add(item ValueType) {
+	m.items = ValueTypeInsert(m.items, item, m.compare)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *ValueTypeSortedMultiset) RemoveOne(item ValueType) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = ValueTypeRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *ValueTypeSortedMultiset) RemoveAll(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *ValueTypeSortedMultiset) Count(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *ValueTypeSortedMultiset) Has(item ValueType) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *ValueTypeSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *ValueTypeSortedMultiset) Distinct() []ValueType {
+	var result []ValueType
+	for i, item := range m.items {
+		if i == 0 || m.compare(result[len(result)-1], item) != 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *ValueTypeSortedMultiset) Range(callback func(item ValueType, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && m.compare(m.items[lo], m.items[hi]) == 0 {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *ValueTypeSortedMultiset) Slice() []ValueType {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *ValueTypeSortedMultiset) Union(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	result := make([]ValueType, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, m.items[i])
+			i++
+		} else if c > 0 {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedMultiset{items: result, compare: m.compare}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *ValueTypeSortedMultiset) Intersect(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedMultiset{items: result, compare: m.compare}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *ValueTypeSortedMultiset) Subtract(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, m.items[i])
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &ValueTypeSortedMultiset{items: result, compare: m.compare}
+}
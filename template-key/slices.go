@@ -0,0 +1,97 @@
+package template_key
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cheekybits/genny/generic"
+	"sort"
+)
+
+type ValueType generic.Type
+
+type KeyType generic.Number
+
+// ValueTypeKeyOf is Delegate type that returns a key of item. Sorting and searching use it to compare items.
+type ValueTypeKeyOf func(item ValueType) KeyType
+
+// ErrValueTypeComparatorContract is returned when items can't be ordered by keys (for example, a key is NaN
+// that is not equal to itself or the sort result is not in order). Use errors.Is to check it.
+var ErrValueTypeComparatorContract = errors.New("comparison method violates its general contract")
+
+// ValueTypeSortByKey sorts an array by keys that keyOf returns.
+// It uses sort.Slice, so it is not stable. slicesgen -stable generates it with sort.SliceStable.
+func ValueTypeSortByKey(a []ValueType, keyOf ValueTypeKeyOf) (err error) {
+	sort.Slice(a, func(i, j int) bool {
+		return keyOf(a[i]) < keyOf(a[j])
+	})
+	for i := range a {
+		key := keyOf(a[i])
+		if key != key {
+			return fmt.Errorf("%w: key is not equal to itself at %d after sort", ErrValueTypeComparatorContract, i)
+		}
+		if i > 0 && key < keyOf(a[i-1]) {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// ValueTypeSearchKey returns first index i that satisfies key <= keyOf(sorted[i]).
+// If there is no such item, it returns len(sorted).
+func ValueTypeSearchKey(sorted []ValueType, key KeyType, keyOf ValueTypeKeyOf) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if keyOf(sorted[h]) < key {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// ValueTypeIndexOfKey returns index of item that has the key. If there is no such item in a sorted slice, it returns -1.
+func ValueTypeIndexOfKey(sorted []ValueType, key KeyType, keyOf ValueTypeKeyOf) int {
+	i := ValueTypeSearchKey(sorted, key, keyOf)
+	if i < len(sorted) && keyOf(sorted[i]) == key {
+		return i
+	}
+	return -1
+}
+
+// ValueTypeContainsKey returns true if item that has the key is in a sorted slice. Otherwise false.
+func ValueTypeContainsKey(sorted []ValueType, key KeyType, keyOf ValueTypeKeyOf) bool {
+	return ValueTypeIndexOfKey(sorted, key, keyOf) != -1
+}
+
+// ValueTypeFindKey returns item that has the key. If there is no such item in a sorted slice, it returns false as a second value.
+func ValueTypeFindKey(sorted []ValueType, key KeyType, keyOf ValueTypeKeyOf) (item ValueType, ok bool) {
+	i := ValueTypeIndexOfKey(sorted, key, keyOf)
+	if i == -1 {
+		return item, false
+	}
+	return sorted[i], true
+}
+
+// ValueTypeInsertByKey inserts item in correct position and returns a sorted slice.
+func ValueTypeInsertByKey(sorted []ValueType, item ValueType, keyOf ValueTypeKeyOf) []ValueType {
+	i := ValueTypeSearchKey(sorted, keyOf(item), keyOf)
+	if i == len(sorted) {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...)
+}
+
+// ValueTypeRemoveKey removes item that has the key in a sorted slice.
+func ValueTypeRemoveKey(sorted []ValueType, key KeyType, keyOf ValueTypeKeyOf) []ValueType {
+	i := ValueTypeIndexOfKey(sorted, key, keyOf)
+	if i == -1 {
+		return sorted
+	}
+	return append(sorted[:i], sorted[i+1:]...)
+}
@@ -0,0 +1,152 @@
+package template_map
+
+import (
+	"github.com/cheekybits/genny/generic"
+)
+
+type KeyType generic.Type
+
+type ValueType generic.Type
+
+// KeyTypeValueTypeSortedMap is a map that keeps keys in a sorted slice.
+// Values are stored in another slice in the same order of keys.
+type KeyTypeValueTypeSortedMap struct {
+	keys   []KeyType
+	values []ValueType
+	lt     func(a, b KeyType) bool
+}
+
+// NewKeyTypeValueTypeSortedMap creates an empty sorted map. lt is used to compare keys.
+func NewKeyTypeValueTypeSortedMap(lt func(a, b KeyType) bool) *KeyTypeValueTypeSortedMap {
+	return &KeyTypeValueTypeSortedMap{lt: lt}
+}
+
+// search returns first index i that satisfies key <= m.keys[i]. If there is no such key, it returns m.Len().
+func (m *KeyTypeValueTypeSortedMap) search(key KeyType) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(m.keys)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if m.lt(m.keys[h], key) {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// indexOf returns index of the key. If the map doesn't have the key, it returns -1.
+func (m *KeyTypeValueTypeSortedMap) indexOf(key KeyType) int {
+	i := m.search(key)
+	if i < len(m.keys) && !m.lt(key, m.keys[i]) {
+		return i
+	}
+	return -1
+}
+
+// Get returns a value of the key. If the map doesn't have the key, it returns false as a second value.
+func (m *KeyTypeValueTypeSortedMap) Get(key KeyType) (value ValueType, ok bool) {
+	i := m.indexOf(key)
+	if i == -1 {
+		return value, false
+	}
+	return m.values[i], true
+}
+
+// Has returns true if the map has the key. Otherwise false.
+func (m *KeyTypeValueTypeSortedMap) Has(key KeyType) bool {
+	return m.indexOf(key) != -1
+}
+
+// Put stores a value of the key. If the map already has the key, it overwrites the value.
+func (m *KeyTypeValueTypeSortedMap) Put(key KeyType, value ValueType) {
+	i := m.search(key)
+	if i < len(m.keys) && !m.lt(key, m.keys[i]) {
+		m.values[i] = value
+		return
+	}
+	m.keys = append(m.keys, key)
+	copy(m.keys[i+1:], m.keys[i:])
+	m.keys[i] = key
+	m.values = append(m.values, value)
+	copy(m.values[i+1:], m.values[i:])
+	m.values[i] = value
+}
+
+// Delete removes the key and its value. It returns false if the map doesn't have the key.
+func (m *KeyTypeValueTypeSortedMap) Delete(key KeyType) bool {
+	i := m.indexOf(key)
+	if i == -1 {
+		return false
+	}
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	m.values = append(m.values[:i], m.values[i+1:]...)
+	return true
+}
+
+// Len returns the number of keys in the map.
+func (m *KeyTypeValueTypeSortedMap) Len() int {
+	return len(m.keys)
+}
+
+// At returns the i-th smallest key and its value.
+func (m *KeyTypeValueTypeSortedMap) At(i int) (KeyType, ValueType) {
+	return m.keys[i], m.values[i]
+}
+
+// Floor returns the greatest key that is less than or equal to the key, and its value.
+// If there is no such key, it returns false as a third value.
+func (m *KeyTypeValueTypeSortedMap) Floor(key KeyType) (floorKey KeyType, value ValueType, ok bool) {
+	i := m.search(key)
+	if i < len(m.keys) && !m.lt(key, m.keys[i]) {
+		return m.keys[i], m.values[i], true
+	}
+	if i == 0 {
+		return floorKey, value, false
+	}
+	return m.keys[i-1], m.values[i-1], true
+}
+
+// Ceiling returns the smallest key that is greater than or equal to the key, and its value.
+// If there is no such key, it returns false as a third value.
+func (m *KeyTypeValueTypeSortedMap) Ceiling(key KeyType) (ceilingKey KeyType, value ValueType, ok bool) {
+	i := m.search(key)
+	if i == len(m.keys) {
+		return ceilingKey, value, false
+	}
+	return m.keys[i], m.values[i], true
+}
+
+// Range calls callback with each keys in [from, to) and their values in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *KeyTypeValueTypeSortedMap) Range(from, to KeyType, callback func(key KeyType, value ValueType) bool) {
+	for i := m.search(from); i < len(m.keys) && m.lt(m.keys[i], to); i++ {
+		if !callback(m.keys[i], m.values[i]) {
+			return
+		}
+	}
+}
+
+// Each calls callback with each keys and values in ascendant order of keys.
+// If callback returns false, Each stops the iteration.
+func (m *KeyTypeValueTypeSortedMap) Each(callback func(key KeyType, value ValueType) bool) {
+	for i, key := range m.keys {
+		if !callback(key, m.values[i]) {
+			return
+		}
+	}
+}
+
+// Keys returns sorted keys. The returned slice shares storage with the map, so don't modify it.
+func (m *KeyTypeValueTypeSortedMap) Keys() []KeyType {
+	return m.keys
+}
+
+// Values returns values in the order of keys. The returned slice shares storage with the map, so don't modify it.
+func (m *KeyTypeValueTypeSortedMap) Values() []ValueType {
+	return m.values
+}
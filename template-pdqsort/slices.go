@@ -0,0 +1,1501 @@
+package template_pdqsort
+
+import (
+	"errors"
+	"fmt"
+	"github.com/cheekybits/genny/generic"
+	"math/bits"
+	"sort"
+)
+
+type ValueType generic.Type
+
+// ValueTypeLessThan is Delegate type that sorting uses as a comparator
+type ValueTypeLessThan func(a, b ValueType) bool
+
+// ErrValueTypeComparatorContract is returned when a comparator doesn't satisfy the contract of strict weak ordering
+// (for example, lt(a, a) returns true or the sort result is not in order). Use errors.Is to check it.
+var ErrValueTypeComparatorContract = errors.New("comparison method violates its general contract")
+
+// ValueTypeSort sorts an array using the provided comparator.
+// It uses pattern-defeating quicksort that is specialized on ValueType, so it is not stable.
+func ValueTypeSort(a []ValueType, lt ValueTypeLessThan) (err error) {
+	n := len(a)
+	pdqsortValueType(a, 0, n, bits.Len(uint(n)), lt)
+	for i := 1; i < len(a); i++ {
+		if lt(a[i], a[i-1]) {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// Pattern-defeating quicksort (pdqsort) is derived from the sort package of the Go standard library,
+// which is based on the original code by Orson Peters:
+//
+// https://github.com/orlp/pdqsort
+//
+// It is specialized on ValueType to avoid the interface calls and index indirection of sort.Slice.
+
+type sortedHintValueType int
+
+const (
+	unknownHintValueType sortedHintValueType = iota
+	increasingHintValueType
+	decreasingHintValueType
+)
+
+type xorshiftValueType uint64
+
+func (r *xorshiftValueType) next() uint64 {
+	*r ^= *r << 13
+	*r ^= *r >> 7
+	*r ^= *r << 17
+	return uint64(*r)
+}
+
+func nextPowerOfTwoValueType(length int) uint {
+	return 1 << bits.Len(uint(length))
+}
+
+// insertionSortValueType sorts a[lo:hi] using insertion sort.
+func insertionSortValueType(a []ValueType, lo, hi int, lt ValueTypeLessThan) {
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && lt(a[j], a[j-1]); j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
+
+// siftDownValueType implements the heap property on a[lo:hi].
+// first is an offset into the array where the root of the heap lies.
+func siftDownValueType(a []ValueType, lo, hi, first int, lt ValueTypeLessThan) {
+	root := lo
+	for {
+		child := 2*root + 1
+		if child >= hi {
+			return
+		}
+		if child+1 < hi && lt(a[first+child], a[first+child+1]) {
+			child++
+		}
+		if !lt(a[first+root], a[first+child]) {
+			return
+		}
+		a[first+root], a[first+child] = a[first+child], a[first+root]
+		root = child
+	}
+}
+
+// heapSortValueType sorts a[lo:hi] using heap sort. pdqsort falls back to it when partitions are unbalanced too often.
+func heapSortValueType(a []ValueType, lo, hi int, lt ValueTypeLessThan) {
+	first := lo
+	n := hi - lo
+	// build heap with greatest element at top
+	for i := (n - 1) / 2; i >= 0; i-- {
+		siftDownValueType(a, i, n, first, lt)
+	}
+	// pop elements, largest first, into end of a
+	for i := n - 1; i >= 0; i-- {
+		a[first], a[first+i] = a[first+i], a[first]
+		siftDownValueType(a, 0, i, first, lt)
+	}
+}
+
+// pdqsortValueType sorts a[lo:hi].
+// limit is the number of allowed bad (very unbalanced) pivots before falling back to heap sort.
+func pdqsortValueType(a []ValueType, lo, hi, limit int, lt ValueTypeLessThan) {
+	const maxInsertion = 12
+
+	var (
+		wasBalanced    = true // whether the last partitioning was reasonably balanced
+		wasPartitioned = true // whether the slice was already partitioned
+	)
+
+	for {
+		length := hi - lo
+
+		if length <= maxInsertion {
+			insertionSortValueType(a, lo, hi, lt)
+			return
+		}
+
+		// fall back to heap sort if too many bad choices were made
+		if limit == 0 {
+			heapSortValueType(a, lo, hi, lt)
+			return
+		}
+
+		// if the last partitioning was imbalanced, we need to break patterns
+		if !wasBalanced {
+			breakPatternsValueType(a, lo, hi)
+			limit--
+		}
+
+		pivot, hint := choosePivotValueType(a, lo, hi, lt)
+		if hint == decreasingHintValueType {
+			reverseRangeValueType(a, lo, hi)
+			// The chosen pivot was pivot-lo elements after the start of the array.
+			// After reversing it is pivot-lo elements before the end of the array.
+			pivot = (hi - 1) - (pivot - lo)
+			hint = increasingHintValueType
+		}
+
+		// the slice is likely already sorted
+		if wasBalanced && wasPartitioned && hint == increasingHintValueType {
+			if partialInsertionSortValueType(a, lo, hi, lt) {
+				return
+			}
+		}
+
+		// Probably the slice contains many duplicate elements, partition the slice into
+		// elements equal to and elements greater than the pivot.
+		if lo > 0 && !lt(a[lo-1], a[pivot]) {
+			lo = partitionEqualValueType(a, lo, hi, pivot, lt)
+			continue
+		}
+
+		mid, alreadyPartitioned := partitionValueType(a, lo, hi, pivot, lt)
+		wasPartitioned = alreadyPartitioned
+
+		leftLen, rightLen := mid-lo, hi-mid
+		balanceThreshold := length / 8
+		if leftLen < rightLen {
+			wasBalanced = leftLen >= balanceThreshold
+			pdqsortValueType(a, lo, mid, limit, lt)
+			lo = mid + 1
+		} else {
+			wasBalanced = rightLen >= balanceThreshold
+			pdqsortValueType(a, mid+1, hi, limit, lt)
+			hi = mid
+		}
+	}
+}
+
+// partitionValueType does one quicksort partition.
+// Let p = a[pivot]. It moves elements in a[lo:hi] around, so that a[i]<p and a[j]>=p for i<newpivot and j>newpivot.
+// On return, a[newpivot] = p.
+func partitionValueType(a []ValueType, lo, hi, pivot int, lt ValueTypeLessThan) (newpivot int, alreadyPartitioned bool) {
+	a[lo], a[pivot] = a[pivot], a[lo]
+	i, j := lo+1, hi-1 // i and j are inclusive of the elements remaining to be partitioned
+
+	for i <= j && lt(a[i], a[lo]) {
+		i++
+	}
+	for i <= j && !lt(a[j], a[lo]) {
+		j--
+	}
+	if i > j {
+		a[j], a[lo] = a[lo], a[j]
+		return j, true
+	}
+	a[i], a[j] = a[j], a[i]
+	i++
+	j--
+
+	for {
+		for i <= j && lt(a[i], a[lo]) {
+			i++
+		}
+		for i <= j && !lt(a[j], a[lo]) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		a[i], a[j] = a[j], a[i]
+		i++
+		j--
+	}
+	a[j], a[lo] = a[lo], a[j]
+	return j, false
+}
+
+// partitionEqualValueType partitions a[lo:hi] into elements equal to a[pivot] followed by elements greater than a[pivot].
+// It assumes that a[lo:hi] does not contain elements smaller than the a[pivot].
+func partitionEqualValueType(a []ValueType, lo, hi, pivot int, lt ValueTypeLessThan) (newpivot int) {
+	a[lo], a[pivot] = a[pivot], a[lo]
+	i, j := lo+1, hi-1 // i and j are inclusive of the elements remaining to be partitioned
+
+	for {
+		for i <= j && !lt(a[lo], a[i]) {
+			i++
+		}
+		for i <= j && lt(a[lo], a[j]) {
+			j--
+		}
+		if i > j {
+			break
+		}
+		a[i], a[j] = a[j], a[i]
+		i++
+		j--
+	}
+	return i
+}
+
+// partialInsertionSortValueType partially sorts a slice, returns true if the slice is sorted at the end.
+func partialInsertionSortValueType(a []ValueType, lo, hi int, lt ValueTypeLessThan) bool {
+	const (
+		maxSteps         = 5  // maximum number of adjacent out-of-order pairs that will get shifted
+		shortestShifting = 50 // don't shift any elements on short arrays
+	)
+	i := lo + 1
+	for j := 0; j < maxSteps; j++ {
+		for i < hi && !lt(a[i], a[i-1]) {
+			i++
+		}
+
+		if i == hi {
+			return true
+		}
+
+		if hi-lo < shortestShifting {
+			return false
+		}
+
+		a[i], a[i-1] = a[i-1], a[i]
+
+		// shift the smaller one to the left
+		if i-lo >= 2 {
+			for j := i - 1; j >= 1; j-- {
+				if !lt(a[j], a[j-1]) {
+					break
+				}
+				a[j], a[j-1] = a[j-1], a[j]
+			}
+		}
+		// shift the greater one to the right
+		if hi-i >= 2 {
+			for j := i + 1; j < hi; j++ {
+				if !lt(a[j], a[j-1]) {
+					break
+				}
+				a[j], a[j-1] = a[j-1], a[j]
+			}
+		}
+	}
+	return false
+}
+
+// breakPatternsValueType scatters some elements around in an attempt to break some patterns
+// that might cause imbalanced partitions in quicksort.
+func breakPatternsValueType(a []ValueType, lo, hi int) {
+	length := hi - lo
+	if length >= 8 {
+		random := xorshiftValueType(length)
+		modulus := nextPowerOfTwoValueType(length)
+
+		idx := lo + (length/4)*2 - 1
+		for i := 0; i < 3; i++ {
+			other := int(uint(random.next()) & (modulus - 1))
+			if other >= length {
+				other -= length
+			}
+			a[idx-1+i], a[lo+other] = a[lo+other], a[idx-1+i]
+		}
+	}
+}
+
+// choosePivotValueType chooses a pivot in a[lo:hi].
+//
+// [0,8): chooses a static pivot.
+// [8,shortestNinther): uses the simple median-of-three method.
+// [shortestNinther,∞): uses the Tukey ninther method.
+func choosePivotValueType(a []ValueType, lo, hi int, lt ValueTypeLessThan) (pivot int, hint sortedHintValueType) {
+	const (
+		shortestNinther = 50
+		maxSwaps        = 4 * 3
+	)
+
+	l := hi - lo
+
+	var (
+		swaps int
+		i     = lo + l/4*1
+		j     = lo + l/4*2
+		k     = lo + l/4*3
+	)
+
+	if l >= 8 {
+		if l >= shortestNinther {
+			// Tukey ninther method, the idea came from Rust's implementation.
+			i = medianAdjacentValueType(a, i, &swaps, lt)
+			j = medianAdjacentValueType(a, j, &swaps, lt)
+			k = medianAdjacentValueType(a, k, &swaps, lt)
+		}
+		// find the median among i, j, k and stores it into j
+		j = medianValueType(a, i, j, k, &swaps, lt)
+	}
+
+	switch swaps {
+	case 0:
+		return j, increasingHintValueType
+	case maxSwaps:
+		return j, decreasingHintValueType
+	default:
+		return j, unknownHintValueType
+	}
+}
+
+// order2ValueType returns x,y where a[x] <= a[y], where x,y=i,j or x,y=j,i.
+func order2ValueType(a []ValueType, i, j int, swaps *int, lt ValueTypeLessThan) (int, int) {
+	if lt(a[j], a[i]) {
+		*swaps++
+		return j, i
+	}
+	return i, j
+}
+
+// medianValueType returns x where a[x] is the median of a[i],a[j],a[k], where x is i, j, or k.
+func medianValueType(a []ValueType, i, j, k int, swaps *int, lt ValueTypeLessThan) int {
+	i, j = order2ValueType(a, i, j, swaps, lt)
+	j, k = order2ValueType(a, j, k, swaps, lt)
+	_, j = order2ValueType(a, i, j, swaps, lt)
+	return j
+}
+
+// medianAdjacentValueType finds the median of a[i-1], a[i], a[i+1] and stores the index into i.
+func medianAdjacentValueType(a []ValueType, i int, swaps *int, lt ValueTypeLessThan) int {
+	return medianValueType(a, i-1, i, i+1, swaps, lt)
+}
+
+func reverseRangeValueType(a []ValueType, lo, hi int) {
+	i := lo
+	j := hi - 1
+	for i < j {
+		a[i], a[j] = a[j], a[i]
+		i++
+		j--
+	}
+}
+
+// ValueTypeValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If ValueTypeSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrValueTypeComparatorContract.
+func ValueTypeValidate(sorted []ValueType, lt ValueTypeLessThan) error {
+	for i, item := range sorted {
+		if lt(item, item) {
+			return fmt.Errorf("%w: item is less than itself at %d", ErrValueTypeComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if lt(item, prev) {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrValueTypeComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// ValueTypeBinarySearch returns first index i that satisfies slices[i] <= item.
+func ValueTypeBinarySearch(sorted []ValueType, item ValueType, lt ValueTypeLessThan) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)-1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if lt(sorted[h], item) {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// ValueTypeLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func ValueTypeLowerBound(sorted []ValueType, item ValueType, lt ValueTypeLessThan) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if lt(sorted[h], item) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// ValueTypeUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func ValueTypeUpperBound(sorted []ValueType, item ValueType, lt ValueTypeLessThan) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if !lt(item, sorted[h]) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopValueType returns first index i that satisfies !(sorted[i] < item) like ValueTypeLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopValueType(sorted []ValueType, item ValueType, lt ValueTypeLessThan) int {
+	if len(sorted) == 0 || !lt(sorted[0], item) {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && lt(sorted[hi], item) {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + ValueTypeLowerBound(sorted[lo+1:hi], item, lt)
+}
+
+// ValueTypeEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func ValueTypeEqualRange(sorted []ValueType, item ValueType, lt ValueTypeLessThan) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, item, lt)
+	hi = lo + ValueTypeUpperBound(sorted[lo:], item, lt)
+	return lo, hi
+}
+
+// ValueTypeCount returns the number of items that are equal to item.
+func ValueTypeCount(sorted []ValueType, item ValueType, lt ValueTypeLessThan) int {
+	lo, hi := ValueTypeEqualRange(sorted, item, lt)
+	return hi - lo
+}
+
+// ValueTypeFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeFloor(sorted []ValueType, item ValueType, lt ValueTypeLessThan) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item, lt)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeCeiling(sorted []ValueType, item ValueType, lt ValueTypeLessThan) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item, lt)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeLower(sorted []ValueType, item ValueType, lt ValueTypeLessThan) (int, bool) {
+	i := ValueTypeLowerBound(sorted, item, lt)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// ValueTypeHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func ValueTypeHigher(sorted []ValueType, item ValueType, lt ValueTypeLessThan) (int, bool) {
+	i := ValueTypeUpperBound(sorted, item, lt)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// ValueTypeRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func ValueTypeRangeIndexes(sorted []ValueType, from, to ValueType, inclusive bool, lt ValueTypeLessThan) (lo, hi int) {
+	lo = ValueTypeLowerBound(sorted, from, lt)
+	if inclusive {
+		hi = lo + ValueTypeUpperBound(sorted[lo:], to, lt)
+	} else {
+		hi = lo + ValueTypeLowerBound(sorted[lo:], to, lt)
+	}
+	return lo, hi
+}
+
+// ValueTypeRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func ValueTypeRange(sorted []ValueType, from, to ValueType, lt ValueTypeLessThan) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, lt)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like ValueTypeRange.
+func ValueTypeRangeInclusive(sorted []ValueType, from, to ValueType, lt ValueTypeLessThan) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, lt)
+	return sorted[lo:hi:hi]
+}
+
+// ValueTypeRemoveRange removes items in [from, to) and returns a sorted slice.
+func ValueTypeRemoveRange(sorted []ValueType, from, to ValueType, lt ValueTypeLessThan) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, lt)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func ValueTypeRemoveRangeInclusive(sorted []ValueType, from, to ValueType, lt ValueTypeLessThan) []ValueType {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, lt)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// ValueTypeCountRange returns the number of items in [from, to).
+func ValueTypeCountRange(sorted []ValueType, from, to ValueType, lt ValueTypeLessThan) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, false, lt)
+	return hi - lo
+}
+
+// ValueTypeCountRangeInclusive returns the number of items in [from, to].
+func ValueTypeCountRangeInclusive(sorted []ValueType, from, to ValueType, lt ValueTypeLessThan) int {
+	lo, hi := ValueTypeRangeIndexes(sorted, from, to, true, lt)
+	return hi - lo
+}
+
+// ValueTypeIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
+func ValueTypeIndexOf(sorted []ValueType, item ValueType, lt ValueTypeLessThan) int {
+	if len(sorted) == 0 {
+		return -1
+	}
+	i := ValueTypeBinarySearch(sorted, item, lt)
+	if !lt(sorted[i], item) && !lt(item, sorted[i]) {
+		return i
+	}
+	return -1
+}
+
+// ValueTypeContains returns true if item is in a sorted slice. Otherwise false.
+func ValueTypeContains(sorted []ValueType, item ValueType, lt ValueTypeLessThan) bool {
+	if len(sorted) == 0 {
+		return false
+	}
+	i := ValueTypeBinarySearch(sorted, item, lt)
+	return !lt(sorted[i], item) && !lt(item, sorted[i])
+}
+
+// ValueTypeInsert inserts item in correct position and returns a sorted slice.
+func ValueTypeInsert(sorted []ValueType, item ValueType, lt ValueTypeLessThan) []ValueType {
+	i := ValueTypeBinarySearch(sorted, item, lt)
+	if i == len(sorted)-1 && lt(sorted[i], item) {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...)
+}
+
+// ValueTypeInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling ValueTypeInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func ValueTypeInsertAll(sorted []ValueType, items []ValueType, lt ValueTypeLessThan) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]ValueType, len(items))
+	copy(batch, items)
+	ValueTypeSort(batch, lt)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && lt(batch[j], sorted[i]) {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]ValueType, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if lt(batch[j], sorted[i]) {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// ValueTypeInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func ValueTypeInsertUnique(sorted []ValueType, item ValueType, lt ValueTypeLessThan) ([]ValueType, bool) {
+	i := ValueTypeLowerBound(sorted, item, lt)
+	if i < len(sorted) && !lt(item, sorted[i]) {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]ValueType{item}, sorted[i:]...)...), true
+}
+
+// ValueTypeRemove removes item in a sorted slice.
+func ValueTypeRemove(sorted []ValueType, item ValueType, lt ValueTypeLessThan) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	i := ValueTypeBinarySearch(sorted, item, lt)
+	if !lt(sorted[i], item) && !lt(item, sorted[i]) {
+		return ValueTypeRemoveAt(sorted, i)
+	}
+	return sorted
+}
+
+// ValueTypeRemoveAt removes item in a slice.
+func ValueTypeRemoveAt(sorted []ValueType, i int) []ValueType {
+	return append(sorted[:i], sorted[i+1:]...)
+}
+
+// ValueTypeRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveAll(sorted []ValueType, items []ValueType, lt ValueTypeLessThan) []ValueType {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && lt(items[j], item) {
+			j++
+		}
+		if j < len(items) && !lt(item, items[j]) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func ValueTypeRemoveIf(sorted []ValueType, pred func(item ValueType) bool) []ValueType {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func ValueTypeRemoveIndexes(sorted []ValueType, indexes []int) []ValueType {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// ValueTypeUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func ValueTypeUnique(sorted []ValueType, lt ValueTypeLessThan) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if lt(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// ValueTypeUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func ValueTypeUniqueFunc(sorted []ValueType, equal func(a, b ValueType) bool) []ValueType {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdValueType is the number of source slices that ValueTypeIterateOver and ValueTypeUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdValueType = 8
+
+// ValueTypeIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
+func ValueTypeIterateOver(lt ValueTypeLessThan, callback func(item ValueType, srcIndex int), sorted ...[]ValueType) {
+	sourceSlices := make([][]ValueType, 0, len(sorted))
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
+		if len(src) > 0 {
+			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
+		}
+	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		iterateOverHeapValueType(lt, callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearValueType(lt, callback, sourceSlices, sliceIndex)
+	}
+}
+
+// iterateOverLinearValueType finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapValueType when there are a few source slices.
+func iterateOverLinearValueType(lt ValueTypeLessThan, callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
+	}
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < len(sourceSlices); i++ {
+			if lt(sourceSlices[i][indexes[i]], minItem) {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		callback(minItem, sliceIndex[minSlice])
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapValueType keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearValueType takes O(N k).
+func iterateOverHeapValueType(lt ValueTypeLessThan, callback func(item ValueType, srcIndex int), sourceSlices [][]ValueType, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if lt(x, y) {
+			return true
+		} else if lt(y, x) {
+			return false
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
+		}
+		down(0)
+	}
+}
+
+// ValueTypeUnion unions sorted slices and returns new slices.
+func ValueTypeUnion(lt ValueTypeLessThan, sorted ...[]ValueType) []ValueType {
+	length := 0
+	sourceSlices := make([][]ValueType, 0, len(sorted))
+	for _, src := range sorted {
+		if len(src) > 0 {
+			length += len(src)
+			sourceSlices = append(sourceSlices, src)
+		}
+	}
+	if length == 0 {
+		return nil
+	} else if len(sourceSlices) == 1 {
+		return sourceSlices[0]
+	}
+	if len(sourceSlices) > heapMergeThresholdValueType {
+		result := make([]ValueType, 0, length)
+		ValueTypeIterateOver(lt, func(item ValueType, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
+	result := make([]ValueType, length)
+	sourceSliceCount := len(sourceSlices)
+	indexes := make([]int, sourceSliceCount)
+	index := 0
+	for {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < sourceSliceCount; i++ {
+			if lt(sourceSlices[i][indexes[i]], minItem) {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		result[index] = minItem
+		index++
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sourceSliceCount--
+			if len(sourceSlices) == 1 {
+				copy(result[index:], sourceSlices[0][indexes[0]:])
+				return result
+			}
+		}
+	}
+}
+
+// ValueTypeUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func ValueTypeUnionDistinct(lt ValueTypeLessThan, sorted ...[]ValueType) []ValueType {
+	var result []ValueType
+	ValueTypeIterateOver(lt, func(item ValueType, srcIndex int) {
+		if len(result) == 0 || lt(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// ValueTypeDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
+func ValueTypeDifference(lt ValueTypeLessThan, sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopValueType(sorted1[i:], sorted2[j], lt)
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i], lt)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if !lt(sorted1[i], sorted2[j]) {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// ValueTypeSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func ValueTypeSymmetricDifference(lt ValueTypeLessThan, sorted1, sorted2 []ValueType) []ValueType {
+	var result []ValueType
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		if lt(sorted1[i], sorted2[j]) {
+			result = append(result, sorted1[i])
+			i++
+		} else if lt(sorted2[j], sorted1[i]) {
+			result = append(result, sorted2[j])
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
+	return result
+}
+
+// ValueTypeDifferenceAll creates difference group of base and all subtract slices and returns.
+func ValueTypeDifferenceAll(lt ValueTypeLessThan, base []ValueType, subtract ...[]ValueType) []ValueType {
+	return ValueTypeDifference(lt, base, ValueTypeUnion(lt, subtract...))
+}
+
+// ValueTypeDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func ValueTypeDiff(lt ValueTypeLessThan, old, new []ValueType) (added, removed, common []ValueType) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		if lt(old[i], new[j]) {
+			removed = append(removed, old[i])
+			i++
+		} else if lt(new[j], old[i]) {
+			added = append(added, new[j])
+			j++
+		} else {
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// ValueTypeIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func ValueTypeIsSubset(lt ValueTypeLessThan, sub, super []ValueType) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopValueType(super[j:], item, lt)
+		if j == len(super) || lt(item, super[j]) {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// ValueTypeIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func ValueTypeIsSuperset(lt ValueTypeLessThan, super, sub []ValueType) bool {
+	return ValueTypeIsSubset(lt, sub, super)
+}
+
+// ValueTypeIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func ValueTypeIsDisjoint(lt ValueTypeLessThan, sorted1, sorted2 []ValueType) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopValueType(sorted1[i:], sorted2[j], lt)
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopValueType(sorted2[j:], sorted1[i], lt)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if !lt(sorted1[i], sorted2[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func ValueTypeEqual(lt ValueTypeLessThan, sorted1, sorted2 []ValueType) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if lt(sorted1[i], sorted2[i]) || lt(sorted2[i], sorted1[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValueTypeIntersectionSize returns the number of items that ValueTypeIntersection returns for sorted1 and sorted2
+// without allocating memory. Like ValueTypeIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func ValueTypeIntersectionSize(lt ValueTypeLessThan, sorted1, sorted2 []ValueType) int {
+	// ValueTypeIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopValueType(long[j:], item, lt)
+		if j == len(long) {
+			break
+		}
+		if !lt(item, long[j]) {
+			count++
+		}
+	}
+	return count
+}
+
+// ValueTypeIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
+func ValueTypeIntersection(lt ValueTypeLessThan, sorted ...[]ValueType) []ValueType {
+	return ValueTypeIntersectionFunc(lt, nil, sorted...)
+}
+
+// ValueTypeIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func ValueTypeIntersectionFunc(lt ValueTypeLessThan, callback func(item ValueType, indexes []int), sorted ...[]ValueType) []ValueType {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
+	})
+	if len(sorted) > heapMergeThresholdValueType {
+		return intersectionHeapValueType(lt, callback, sorted, order[0])
+	}
+	return intersectionLinearValueType(lt, callback, sorted, order)
+}
+
+// intersectionLinearValueType checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapValueType when there are a few source slices or the items are spread evenly.
+func intersectionLinearValueType(lt ValueTypeLessThan, callback func(item ValueType, indexes []int), sorted [][]ValueType, order []int) []ValueType {
+	var result []ValueType
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopValueType(src[cursors[i]:], value, lt)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if lt(value, src[cursors[i]]) {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapValueType keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearValueType when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapValueType(lt ValueTypeLessThan, callback func(item ValueType, indexes []int), sorted [][]ValueType, shortest int) []ValueType {
+	var result []ValueType
+	if len(sorted[shortest]) == 0 {
+		return result
+	}
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if lt(maxItem, sorted[i][0]) {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return lt(sorted[a][cursors[a]], sorted[b][cursors[b]])
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if lt(sorted[src][cursors[src]], maxItem) {
+			cursors[src] += gallopValueType(sorted[src][cursors[src]:], maxItem, lt)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; lt(maxItem, item) {
+				maxItem = item
+			}
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && !lt(maxItem, s[cursors[shortest]]) {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
+			}
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
+				break
+			}
+		}
+	}
+}
+
+// ValueTypeSortedSet is a set that keeps unique items in a sorted slice.
+type ValueTypeSortedSet struct {
+	items []ValueType
+	lt    ValueTypeLessThan
+}
+
+// NewValueTypeSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewValueTypeSortedSet(lt ValueTypeLessThan, items ...ValueType) *ValueTypeSortedSet {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted, lt)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || lt(unique[len(unique)-1], item) {
+			unique = append(unique, item)
+		}
+	}
+	return &ValueTypeSortedSet{items: unique, lt: lt}
+}
+
+// search returns first index i that satisfies item <= s.items[i]. If there is no such item, it returns s.Len().
+func (s *ValueTypeSortedSet) search(item ValueType) int {
+	i, j := 0, len(s.items)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if s.lt(s.items[h], item) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *ValueTypeSortedSet) Add(item ValueType) bool {
+	i := s.search(item)
+	if i < len(s.items) && !s.lt(item, s.items[i]) {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *ValueTypeSortedSet) Delete(item ValueType) bool {
+	i := s.search(item)
+	if i == len(s.items) || s.lt(item, s.items[i]) {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *ValueTypeSortedSet) Has(item ValueType) bool {
+	i := s.search(item)
+	return i < len(s.items) && !s.lt(item, s.items[i])
+}
+
+// Len returns the number of items in the set.
+func (s *ValueTypeSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *ValueTypeSortedSet) At(i int) ValueType {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *ValueTypeSortedSet) Range(callback func(i int, item ValueType) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *ValueTypeSortedSet) Slice() []ValueType {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *ValueTypeSortedSet) Union(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	result := make([]ValueType, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.lt(s.items[i], other.items[j]) {
+			result = append(result, s.items[i])
+			i++
+		} else if s.lt(other.items[j], s.items[i]) {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedSet{items: result, lt: s.lt}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *ValueTypeSortedSet) Intersect(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.lt(s.items[i], other.items[j]) {
+			i++
+		} else if s.lt(other.items[j], s.items[i]) {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedSet{items: result, lt: s.lt}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *ValueTypeSortedSet) Subtract(other *ValueTypeSortedSet) *ValueTypeSortedSet {
+	var result []ValueType
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.lt(s.items[i], other.items[j]) {
+			result = append(result, s.items[i])
+			i++
+		} else if s.lt(other.items[j], s.items[i]) {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &ValueTypeSortedSet{items: result, lt: s.lt}
+}
+
+// ValueTypeSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type ValueTypeSortedMultiset struct {
+	items []ValueType
+	lt    ValueTypeLessThan
+}
+
+// NewValueTypeSortedMultiset creates a sorted multiset that has items.
+func NewValueTypeSortedMultiset(lt ValueTypeLessThan, items ...ValueType) *ValueTypeSortedMultiset {
+	sorted := make([]ValueType, len(items))
+	copy(sorted, items)
+	ValueTypeSort(sorted, lt)
+	return &ValueTypeSortedMultiset{items: sorted, lt: lt}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *ValueTypeSortedMultiset) equalRange(item ValueType) (lo, hi int) {
+	return ValueTypeEqualRange(m.items, item, m.lt)
+}
+
+// Add adds item to the multiset.
+func (m *ValueTypeSortedMultiset) Add(item ValueType) {
+	m.items = ValueTypeInsert(m.items, item, m.lt)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *ValueTypeSortedMultiset) RemoveOne(item ValueType) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = ValueTypeRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *ValueTypeSortedMultiset) RemoveAll(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *ValueTypeSortedMultiset) Count(item ValueType) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *ValueTypeSortedMultiset) Has(item ValueType) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *ValueTypeSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *ValueTypeSortedMultiset) Distinct() []ValueType {
+	var result []ValueType
+	for i, item := range m.items {
+		if i == 0 || m.lt(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *ValueTypeSortedMultiset) Range(callback func(item ValueType, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && !m.lt(m.items[lo], m.items[hi]) {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *ValueTypeSortedMultiset) Slice() []ValueType {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *ValueTypeSortedMultiset) Union(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	result := make([]ValueType, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.lt(m.items[i], other.items[j]) {
+			result = append(result, m.items[i])
+			i++
+		} else if m.lt(other.items[j], m.items[i]) {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &ValueTypeSortedMultiset{items: result, lt: m.lt}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *ValueTypeSortedMultiset) Intersect(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.lt(m.items[i], other.items[j]) {
+			i++
+		} else if m.lt(other.items[j], m.items[i]) {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &ValueTypeSortedMultiset{items: result, lt: m.lt}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *ValueTypeSortedMultiset) Subtract(other *ValueTypeSortedMultiset) *ValueTypeSortedMultiset {
+	var result []ValueType
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.lt(m.items[i], other.items[j]) {
+			result = append(result, m.items[i])
+			i++
+		} else if m.lt(other.items[j], m.items[i]) {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &ValueTypeSortedMultiset{items: result, lt: m.lt}
+}
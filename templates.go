@@ -0,0 +1,104 @@
+package slices
+
+import (
+	"embed"
+	"fmt"
+)
+
+//go:embed template/slices.go template-timsort/slices.go template-comparable/slices.go template-comparable-timsort/slices.go
+//go:embed template-compare/slices.go template-compare-timsort/slices.go template-key/slices.go
+//go:embed template-map/slices.go template-comparable-map/slices.go
+//go:embed template-pdqsort/slices.go template-comparable-pdqsort/slices.go
+//go:embed template/safe.go template-timsort/safe.go template-comparable/safe.go template-comparable-timsort/safe.go
+//go:embed template-compare/safe.go template-compare-timsort/safe.go
+//go:embed template-pdqsort/safe.go template-comparable-pdqsort/safe.go
+//go:embed template-comparable/radix.go template-comparable/radix_string.go
+//go:embed template-comparable-timsort/radix.go template-comparable-timsort/radix_string.go
+//go:embed template-comparable-pdqsort/radix.go template-comparable-pdqsort/radix_string.go
+var templateFiles embed.FS
+
+// TemplateNames is a list of template names that Template accepts.
+var TemplateNames = []string{
+	"standard",
+	"timsort",
+	"comparable",
+	"comparable-timsort",
+	"compare",
+	"compare-timsort",
+	"pdqsort",
+	"comparable-pdqsort",
+	"key",
+	"map",
+	"comparable-map",
+}
+
+var templatePaths = map[string]string{
+	"standard":           "template/slices.go",
+	"timsort":            "template-timsort/slices.go",
+	"comparable":         "template-comparable/slices.go",
+	"comparable-timsort": "template-comparable-timsort/slices.go",
+	"compare":            "template-compare/slices.go",
+	"compare-timsort":    "template-compare-timsort/slices.go",
+	"pdqsort":            "template-pdqsort/slices.go",
+	"comparable-pdqsort": "template-comparable-pdqsort/slices.go",
+	"key":                "template-key/slices.go",
+	"map":                "template-map/slices.go",
+	"comparable-map":     "template-comparable-map/slices.go",
+}
+
+// safeTemplatePaths has templates of safe API that slicesgen -safe appends to the template.
+var safeTemplatePaths = map[string]string{
+	"standard":           "template/safe.go",
+	"timsort":            "template-timsort/safe.go",
+	"comparable":         "template-comparable/safe.go",
+	"comparable-timsort": "template-comparable-timsort/safe.go",
+	"compare":            "template-compare/safe.go",
+	"compare-timsort":    "template-compare-timsort/safe.go",
+	"pdqsort":            "template-pdqsort/safe.go",
+	"comparable-pdqsort": "template-comparable-pdqsort/safe.go",
+}
+
+// radixTemplateDirs has directories of radix sort templates that slicesgen -radix appends to the template.
+var radixTemplateDirs = map[string]string{
+	"comparable":         "template-comparable",
+	"comparable-timsort": "template-comparable-timsort",
+	"comparable-pdqsort": "template-comparable-pdqsort",
+}
+
+// Template returns source code of the template that is bundled in this package.
+func Template(name string) ([]byte, error) {
+	path, ok := templatePaths[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template: %s", name)
+	}
+	return templateFiles.ReadFile(path)
+}
+
+// SafeTemplate returns source code of the safe API template for the template name.
+// It returns an error if the template doesn't have safe API.
+func SafeTemplate(name string) ([]byte, error) {
+	path, ok := safeTemplatePaths[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s doesn't support safe mode", name)
+	}
+	return templateFiles.ReadFile(path)
+}
+
+// RadixTemplate returns source code of the radix sort template for the template name.
+// If stringType is true, it returns MSD radix sort for strings. Otherwise it returns LSD radix sort for integers.
+// It returns an error if the template doesn't have radix sort.
+func RadixTemplate(name string, stringType bool) ([]byte, error) {
+	dir, ok := radixTemplateDirs[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s doesn't support radix sort", name)
+	}
+	return templateFiles.ReadFile(dir + "/" + RadixTemplateFile(stringType))
+}
+
+// RadixTemplateFile returns file name of the radix sort template in a template directory.
+func RadixTemplateFile(stringType bool) string {
+	if stringType {
+		return "radix_string.go"
+	}
+	return "radix.go"
+}
@@ -0,0 +1,1253 @@
+package comparablepdqsort
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"reflect"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func deepEqual(v1, v2 []int) bool {
+	if len(v1) == 0 && len(v2) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(v1, v2)
+}
+
+func TestSortInt(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("sort returns stable", prop.ForAll(func(input []int) bool {
+		timSort := make([]int, len(input))
+		defaultSort := make([]int, len(input))
+		copy(timSort, input)
+		copy(defaultSort, input)
+
+		IntSort(timSort)
+		sort.Ints(defaultSort)
+		return deepEqual(timSort, defaultSort)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestBinarySearch(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("binary search found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		orig := make([]int, len(input))
+		copy(orig, input)
+		IntSort(input)
+		i := IntBinarySearch(input, value)
+		if input[i] != value {
+			t.Log(i, value, input[i])
+			t.Log(orig)
+			t.Log(input)
+		}
+		return input[i] == value
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIndexOf(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("indexOf found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		IntSort(input)
+		i := IntIndexOf(input, value)
+		return i != -1 && input[i] == value
+	}, numSliceGenerator))
+
+	properties.Property("indexOf returns -1 if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		array := input[1:]
+		IntSort(array)
+		i := IntIndexOf(array, value)
+		return i == -1
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestContains(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("contains returns true if item found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		IntSort(input)
+		return IntContains(input, value)
+	}, numSliceGenerator))
+
+	properties.Property("indexOf returns false if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		array := input[1:]
+		IntSort(array)
+		return !IntContains(array, value)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestInsert(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("insert returns new sorted slices", prop.ForAll(func(input []int) bool {
+		expected := make([]int, len(input))
+		copy(expected, input)
+		IntSort(expected)
+
+		value := input[0]
+		array := input[1:]
+		IntSort(array)
+
+		inserted := IntInsert(array, value)
+
+		return reflect.DeepEqual(expected, inserted)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRemove(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("remove removes item of array", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		IntSort(input)
+
+		removedArray := IntRemove(input, value)
+
+		return len(removedArray) == len(input) -1 && !IntContains(removedArray, value)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestUnion(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("union item of slices", prop.ForAll(func(input1, input2, input3 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		IntSort(input3)
+
+		union := IntUnion(input1, input2, input3)
+
+		if len(union) == 0 {
+			return true
+		}
+
+		expected := make([]int, len(union))
+		copy(expected, union)
+		IntSort(expected)
+
+		return reflect.DeepEqual(expected, union)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestDifference(t *testing.T) {
+	result := IntDifference([]int{10, 20, 30, 40}, []int{20, 30})
+	if len(result) != 2 {
+		t.Error("length should be 2")
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("intersection item of slices", prop.ForAll(func(src1, src2, common []int) bool {
+		IntSort(src1)
+		IntSort(src2)
+		IntSort(common)
+
+		src1 = IntDifference(src1, src2)
+		common = IntDifference(common, src2)
+
+		input1 := IntUnion(src1, common)
+		input2 := IntUnion(src2, common)
+
+		actual := IntIntersection(input1, input2)
+		if len(actual) != len(common) {
+			return false
+		}
+		return deepEqual(common, actual)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIterateOver(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("iterate item of slices", prop.ForAll(func(input1, input2, input3 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		IntSort(input3)
+
+		var result []int
+		IntIterateOver(func(item, srcIndex int) {
+			result = append(result, item)
+		}, input1, input2, input3)
+
+		if len(result) == 0 {
+			return true
+		}
+
+		expected := make([]int, len(result))
+		copy(expected, result)
+		IntSort(expected)
+
+		return reflect.DeepEqual(expected, result)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func benchmarkIntContains(b *testing.B, count int) {
+	slice := make([]int, count)
+	for i := 0; i < count; i++ {
+		slice[i] = rand.Int()
+	}
+	value := slice[0]
+	IntSort(slice)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = IntContains(slice, value)
+	}
+}
+
+func BenchmarkIntContains100(b *testing.B) {
+	benchmarkIntContains(b, 20)
+}
+
+func benchmarkMapContains(b *testing.B, count int) {
+	m := make(map[int]bool, count)
+	var value int
+	for i := 0; i < count; i++ {
+		v := rand.Int()
+		if i == 0 {
+			value = v
+		}
+		m[v] = true
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = m[value]
+	}
+}
+
+func BenchmarkMapContains100(b *testing.B) {
+	benchmarkMapContains(b, 20)
+}
+
+func uniqueSortedInts(input ...[]int) []int {
+	m := make(map[int]bool)
+	for _, src := range input {
+		for _, v := range src {
+			m[v] = true
+		}
+	}
+	result := make([]int, 0, len(m))
+	for v := range m {
+		result = append(result, v)
+	}
+	sort.Ints(result)
+	return result
+}
+
+func TestSortedSet(t *testing.T) {
+	numberGenerator := gen.IntRange(-20, 20)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("sorted set keeps unique sorted items", prop.ForAll(func(input []int) bool {
+		set := NewIntSortedSet(input...)
+		expected := uniqueSortedInts(input)
+		if set.Len() != len(expected) {
+			return false
+		}
+		var actual []int
+		set.Range(func(i int, item int) bool {
+			actual = append(actual, item)
+			return set.At(i) == item
+		})
+		return deepEqual(expected, actual) && deepEqual(expected, set.Slice())
+	}, numSliceGenerator))
+
+	properties.Property("add inserts only new items", prop.ForAll(func(input []int) bool {
+		set := NewIntSortedSet()
+		m := make(map[int]bool)
+		for _, v := range input {
+			if set.Add(v) == m[v] {
+				return false
+			}
+			m[v] = true
+		}
+		return deepEqual(uniqueSortedInts(input), set.Slice())
+	}, numSliceGenerator))
+
+	properties.Property("delete removes existing items", prop.ForAll(func(input []int, value int) bool {
+		set := NewIntSortedSet(input...)
+		has := set.Has(value)
+		if set.Delete(value) != has {
+			return false
+		}
+		return !set.Has(value) && !set.Delete(value)
+	}, numSliceGenerator, numberGenerator))
+
+	properties.Property("set operations", prop.ForAll(func(input1, input2 []int) bool {
+		set1 := NewIntSortedSet(input1...)
+		set2 := NewIntSortedSet(input2...)
+		var intersection, subtraction []int
+		for _, v := range set1.Slice() {
+			if set2.Has(v) {
+				intersection = append(intersection, v)
+			} else {
+				subtraction = append(subtraction, v)
+			}
+		}
+		return deepEqual(uniqueSortedInts(input1, input2), set1.Union(set2).Slice()) &&
+			deepEqual(intersection, set1.Intersect(set2).Slice()) &&
+			deepEqual(subtraction, set1.Subtract(set2).Slice())
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func countInts(input []int) map[int]int {
+	counts := make(map[int]int)
+	for _, v := range input {
+		counts[v]++
+	}
+	return counts
+}
+
+func checkMultiset(m *IntSortedMultiset, counts map[int]int) bool {
+	total := 0
+	for v, c := range counts {
+		if m.Count(v) != c {
+			return false
+		}
+		total += c
+	}
+	expected := make([]int, len(m.Slice()))
+	copy(expected, m.Slice())
+	sort.Ints(expected)
+	return m.Len() == total && deepEqual(expected, m.Slice())
+}
+
+func TestSortedMultiset(t *testing.T) {
+	numberGenerator := gen.IntRange(-10, 10)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("multiset counts items", prop.ForAll(func(input []int) bool {
+		m := NewIntSortedMultiset(input...)
+		added := NewIntSortedMultiset()
+		for _, v := range input {
+			added.Add(v)
+		}
+		counts := countInts(input)
+		var distinct []int
+		ok := true
+		m.Range(func(item, count int) bool {
+			distinct = append(distinct, item)
+			ok = ok && counts[item] == count
+			return true
+		})
+		return ok && checkMultiset(m, counts) && checkMultiset(added, counts) &&
+			deepEqual(uniqueSortedInts(input), m.Distinct()) && deepEqual(uniqueSortedInts(input), distinct)
+	}, numSliceGenerator))
+
+	properties.Property("remove items", prop.ForAll(func(input []int, value int) bool {
+		m := NewIntSortedMultiset(input...)
+		counts := countInts(input)
+		if m.RemoveOne(value) != (counts[value] > 0) {
+			return false
+		}
+		if counts[value] > 0 {
+			counts[value]--
+		}
+		if !checkMultiset(m, counts) {
+			return false
+		}
+		if m.RemoveAll(value) != counts[value] {
+			return false
+		}
+		counts[value] = 0
+		return !m.Has(value) && checkMultiset(m, counts)
+	}, numSliceGenerator, numberGenerator))
+
+	properties.Property("multiset operations", prop.ForAll(func(input1, input2 []int) bool {
+		m1 := NewIntSortedMultiset(input1...)
+		m2 := NewIntSortedMultiset(input2...)
+		counts1 := countInts(input1)
+		counts2 := countInts(input2)
+		union := make(map[int]int)
+		intersection := make(map[int]int)
+		subtraction := make(map[int]int)
+		for v := range counts1 {
+			union[v] = counts1[v]
+			if counts2[v] > counts1[v] {
+				union[v] = counts2[v]
+				intersection[v] = counts1[v]
+			} else {
+				intersection[v] = counts2[v]
+				subtraction[v] = counts1[v] - counts2[v]
+			}
+		}
+		for v := range counts2 {
+			if counts1[v] == 0 {
+				union[v] = counts2[v]
+			}
+		}
+		return checkMultiset(m1.Union(m2), union) &&
+			checkMultiset(m1.Intersect(m2), intersection) &&
+			checkMultiset(m1.Subtract(m2), subtraction)
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestEqualRange(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 10)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("lower bound and upper bound match linear scan", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		lower, upper := len(input), len(input)
+		for i := len(input) - 1; i >= 0; i-- {
+			if input[i] >= value {
+				lower = i
+			}
+			if input[i] > value {
+				upper = i
+			}
+		}
+		return IntLowerBound(input, value) == lower && IntUpperBound(input, value) == upper
+	}, numSliceGenerator, numberGenerator))
+
+	properties.Property("equal range covers all equal items", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		lo, hi := IntEqualRange(input, value)
+		if lo > hi || hi > len(input) {
+			return false
+		}
+		for i, item := range input {
+			if (lo <= i && i < hi) != (item == value) {
+				return false
+			}
+		}
+		return IntCount(input, value) == countInts(input)[value]
+	}, numSliceGenerator, numberGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestFloorCeiling(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 10)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("floor, ceiling, lower and higher match linear scan", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		floor, ceiling, lower, higher := -1, -1, -1, -1
+		for i, item := range input {
+			if item <= value {
+				floor = i
+			}
+			if item < value {
+				lower = i
+			}
+		}
+		for i := len(input) - 1; i >= 0; i-- {
+			if input[i] >= value {
+				ceiling = i
+			}
+			if input[i] > value {
+				higher = i
+			}
+		}
+		check := func(expected, actual int, ok bool) bool {
+			return expected == actual && ok == (expected != -1)
+		}
+		i, ok := IntFloor(input, value)
+		if !check(floor, i, ok) {
+			return false
+		}
+		i, ok = IntCeiling(input, value)
+		if !check(ceiling, i, ok) {
+			return false
+		}
+		i, ok = IntLower(input, value)
+		if !check(lower, i, ok) {
+			return false
+		}
+		i, ok = IntHigher(input, value)
+		return check(higher, i, ok)
+	}, numSliceGenerator, numberGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRange(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 10)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("range matches linear scan", prop.ForAll(func(input []int, from, to int) bool {
+		IntSort(input)
+		var halfOpen, closed, restHalfOpen, restClosed []int
+		for _, item := range input {
+			if from <= item && item < to {
+				halfOpen = append(halfOpen, item)
+			} else {
+				restHalfOpen = append(restHalfOpen, item)
+			}
+			if from <= item && item <= to {
+				closed = append(closed, item)
+			} else {
+				restClosed = append(restClosed, item)
+			}
+		}
+		if !deepEqual(IntRange(input, from, to), halfOpen) || !deepEqual(IntRangeInclusive(input, from, to), closed) {
+			return false
+		}
+		if IntCountRange(input, from, to) != len(halfOpen) || IntCountRangeInclusive(input, from, to) != len(closed) {
+			return false
+		}
+		input2 := make([]int, len(input))
+		copy(input2, input)
+		return deepEqual(IntRemoveRange(input, from, to), restHalfOpen) &&
+			deepEqual(IntRemoveRangeInclusive(input2, from, to), restClosed)
+	}, numSliceGenerator, numberGenerator, numberGenerator))
+
+	properties.Property("appending to range doesn't overwrite following items", prop.ForAll(func(input []int) bool {
+		IntSort(input)
+		orig := make([]int, len(input))
+		copy(orig, input)
+		_ = append(IntRange(input, 3, 6), -1)
+		return deepEqual(input, orig)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestInsertAll(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 20)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("insertAll returns sorted slice", prop.ForAll(func(input, items []int) bool {
+		IntSort(input)
+		expected := append(append([]int{}, input...), items...)
+		sort.Ints(expected)
+		origItems := append([]int{}, items...)
+		result := IntInsertAll(input, items)
+		return deepEqual(result, expected) && deepEqual(items, origItems)
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.Property("insertAll merges in place with enough capacity", prop.ForAll(func(input, items []int) bool {
+		IntSort(input)
+		expected := append(append([]int{}, input...), items...)
+		sort.Ints(expected)
+		sorted := make([]int, len(input), len(input)+len(items))
+		copy(sorted, input)
+		result := IntInsertAll(sorted, items)
+		return deepEqual(result, expected) && (len(result) == 0 || &result[0] == &sorted[:1][0])
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRemoveAll(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 20)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("removeAll removes all equal items", prop.ForAll(func(input, items []int) bool {
+		IntSort(input)
+		IntSort(items)
+		removes := countInts(items)
+		var expected []int
+		for _, item := range input {
+			if removes[item] == 0 {
+				expected = append(expected, item)
+			}
+		}
+		return deepEqual(IntRemoveAll(input, items), expected)
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.Property("removeIf removes items that satisfy predicate", prop.ForAll(func(input []int) bool {
+		IntSort(input)
+		var expected []int
+		for _, item := range input {
+			if item%3 != 0 {
+				expected = append(expected, item)
+			}
+		}
+		return deepEqual(IntRemoveIf(input, func(item int) bool { return item%3 == 0 }), expected)
+	}, numSliceGenerator))
+
+	properties.Property("removeIndexes removes items at indexes", prop.ForAll(func(input, indexes []int) bool {
+		IntSort(input)
+		removes := countInts(indexes)
+		var expected []int
+		for i, item := range input {
+			if removes[i] == 0 {
+				expected = append(expected, item)
+			}
+		}
+		return deepEqual(IntRemoveIndexes(input, indexes), expected)
+	}, numSliceGenerator, gen.SliceOf(gen.IntRange(-2, 25))))
+
+	properties.TestingRun(t)
+}
+
+func TestUnique(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 20)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("unique removes duplicated items", prop.ForAll(func(input []int) bool {
+		IntSort(input)
+		expected := uniqueSortedInts(input)
+		return deepEqual(IntUnique(input), expected)
+	}, numSliceGenerator))
+
+	properties.Property("uniqueFunc removes items that equal func reports", prop.ForAll(func(input []int) bool {
+		IntSort(input)
+		var expected []int
+		for _, item := range input {
+			if len(expected) == 0 || expected[len(expected)-1]/3 != item/3 {
+				expected = append(expected, item)
+			}
+		}
+		result := IntUniqueFunc(input, func(a, b int) bool { return a/3 == b/3 })
+		return deepEqual(result, expected)
+	}, numSliceGenerator))
+
+	properties.Property("insertUnique inserts only new items", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		input = IntUnique(input)
+		has := countInts(input)[value] > 0
+		expected := uniqueSortedInts(input, []int{value})
+		result, inserted := IntInsertUnique(input, value)
+		return inserted != has && deepEqual(result, expected)
+	}, numSliceGenerator, numberGenerator))
+
+	properties.Property("unionDistinct returns each item once", prop.ForAll(func(input1, input2, input3 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		IntSort(input3)
+		expected := uniqueSortedInts(input1, input2, input3)
+		return deepEqual(IntUnionDistinct(input1, input2, input3), expected)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func subtractCounts(a, b map[int]int) map[int]int {
+	result := make(map[int]int)
+	for k, v := range a {
+		if v > b[k] {
+			result[k] = v - b[k]
+		}
+	}
+	return result
+}
+
+func expandCounts(counts map[int]int) []int {
+	var result []int
+	for k, v := range counts {
+		for i := 0; i < v; i++ {
+			result = append(result, k)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 20)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("symmetricDifference returns items only in one side", prop.ForAll(func(input1, input2 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		count1, count2 := countInts(input1), countInts(input2)
+		expected := append(expandCounts(subtractCounts(count1, count2)), expandCounts(subtractCounts(count2, count1))...)
+		sort.Ints(expected)
+		return deepEqual(IntSymmetricDifference(input1, input2), expected)
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.Property("differenceAll subtracts all slices", prop.ForAll(func(base, input1, input2 []int) bool {
+		IntSort(base)
+		IntSort(input1)
+		IntSort(input2)
+		expected := expandCounts(subtractCounts(subtractCounts(countInts(base), countInts(input1)), countInts(input2)))
+		return deepEqual(IntDifferenceAll(base, input1, input2), expected) && deepEqual(IntDifferenceAll(base), base)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.Property("diff returns added, removed and common items", prop.ForAll(func(old, new []int) bool {
+		IntSort(old)
+		IntSort(new)
+		oldCount, newCount := countInts(old), countInts(new)
+		added, removed, common := IntDiff(old, new)
+		return deepEqual(added, expandCounts(subtractCounts(newCount, oldCount))) &&
+			deepEqual(removed, expandCounts(subtractCounts(oldCount, newCount))) &&
+			deepEqual(common, expandCounts(subtractCounts(oldCount, subtractCounts(oldCount, newCount))))
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestSetPredicates(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 10)
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("predicates match counts", prop.ForAll(func(input1, input2 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		count1, count2 := countInts(input1), countInts(input2)
+		subset, disjoint := true, true
+		for k, v := range count1 {
+			if v > count2[k] {
+				subset = false
+			}
+			if count2[k] > 0 {
+				disjoint = false
+			}
+		}
+		// like Intersection, each item of the shorter slice is counted if it is in the other slice
+		shorter, otherCount := input1, count2
+		if len(input2) < len(input1) {
+			shorter, otherCount = input2, count1
+		}
+		size := 0
+		for _, item := range shorter {
+			if otherCount[item] > 0 {
+				size++
+			}
+		}
+		return IntIsSubset(input1, input2) == subset &&
+			IntIsSuperset(input2, input1) == subset &&
+			IntIsDisjoint(input1, input2) == disjoint &&
+			IntIntersectionSize(input1, input2) == size &&
+			IntEqual(input1, input2) == deepEqual(input1, input2)
+	}, numSliceGenerator, numSliceGenerator))
+
+	properties.Property("slice is subset of its superset", prop.ForAll(func(input1, input2 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		union := IntUnion(input1, input2)
+		copied := append([]int{}, input1...)
+		return IntIsSubset(input1, union) && IntIsSuperset(union, input2) && IntEqual(input1, copied)
+	}, numSliceGenerator, numSliceGenerator))
+
+	// a few distinct values make many duplicated items in both slices
+	duplicatedSliceGenerator := gen.SliceOf(gen.IntRange(0, 3))
+
+	properties.Property("intersection size equals length of intersection", prop.ForAll(func(input1, input2 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		return IntIntersectionSize(input1, input2) == len(IntIntersection(input1, input2)) &&
+			IntIntersectionSize(input2, input1) == len(IntIntersection(input2, input1))
+	}, duplicatedSliceGenerator, duplicatedSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func checkIterateOver(sources [][]int, iterate func(callback func(item, srcIndex int))) bool {
+	cursors := make([]int, len(sources))
+	ok := true
+	lastItem, lastSrc := 0, -1
+	iterate(func(item, srcIndex int) {
+		if cursors[srcIndex] >= len(sources[srcIndex]) || sources[srcIndex][cursors[srcIndex]] != item {
+			ok = false
+			return
+		}
+		cursors[srcIndex]++
+		if lastSrc != -1 && (item < lastItem || (item == lastItem && srcIndex < lastSrc)) {
+			ok = false
+		}
+		lastItem, lastSrc = item, srcIndex
+	})
+	for i, src := range sources {
+		if cursors[i] != len(src) {
+			return false
+		}
+	}
+	return ok
+}
+
+func TestIterateOverManySources(t *testing.T) {
+	numSliceGenerator := gen.SliceOf(gen.IntRange(0, 20))
+
+	properties := gopter.NewProperties(nil)
+
+	for _, count := range []int{1, 3, 20} {
+		properties.Property(fmt.Sprintf("iterate over %d slices in stable order", count), prop.ForAll(func(sources [][]int) bool {
+			var expected []int
+			for _, src := range sources {
+				IntSort(src)
+				expected = append(expected, src...)
+			}
+			sort.Ints(expected)
+			return checkIterateOver(sources, func(callback func(item, srcIndex int)) {
+				IntIterateOver(callback, sources...)
+			}) && deepEqual(IntUnion(sources...), expected)
+		}, gen.SliceOfN(count, numSliceGenerator)))
+	}
+
+	properties.TestingRun(t)
+}
+
+func TestGallop(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 500)
+	smallSliceGenerator := gen.SliceOf(numberGenerator)
+	largeSliceGenerator := gen.SliceOfN(300, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("gallop returns lower bound", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		return gallopInt(input, value) == IntLowerBound(input, value)
+	}, largeSliceGenerator, numberGenerator))
+
+	properties.Property("set operations work with skewed sizes", prop.ForAll(func(small, large []int) bool {
+		IntSort(small)
+		IntSort(large)
+		smallCount, largeCount := countInts(small), countInts(large)
+		matched := 0
+		for k, v := range smallCount {
+			if v < largeCount[k] {
+				matched += v
+			} else {
+				matched += largeCount[k]
+			}
+		}
+		var intersection []int
+		for _, item := range small {
+			if largeCount[item] > 0 {
+				intersection = append(intersection, item)
+			}
+		}
+		return deepEqual(IntDifference(small, large), expandCounts(subtractCounts(smallCount, largeCount))) &&
+			deepEqual(IntDifference(large, small), expandCounts(subtractCounts(largeCount, smallCount))) &&
+			deepEqual(IntIntersection(small, large), intersection) &&
+			IntIntersectionSize(small, large) == len(intersection) &&
+			IntIntersectionSize(large, small) == len(intersection) &&
+			IntIsDisjoint(small, large) == (matched == 0) &&
+			IntIsSubset(small, large) == (matched == len(small))
+	}, smallSliceGenerator, largeSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestIntersectionFunc(t *testing.T) {
+	numSliceGenerator := gen.SliceOf(gen.IntRange(0, 30))
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("intersection doesn't reorder argument", prop.ForAll(func(input1, input2, input3 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		IntSort(input3)
+		sources := [][]int{input1, input2, input3}
+		IntIntersection(sources...)
+		return deepEqual(sources[0], input1) && deepEqual(sources[1], input2) && deepEqual(sources[2], input3)
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.Property("intersectionFunc reports indexes in each source", prop.ForAll(func(input1, input2, input3 []int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		IntSort(input3)
+		sources := [][]int{input1, input2, input3}
+		var items []int
+		ok := true
+		result := IntIntersectionFunc(func(item int, indexes []int) {
+			items = append(items, item)
+			for i, src := range sources {
+				if src[indexes[i]] != item {
+					ok = false
+				}
+			}
+		}, sources...)
+		return ok && deepEqual(result, items) && deepEqual(result, IntIntersection(sources...))
+	}, numSliceGenerator, numSliceGenerator, numSliceGenerator))
+
+	properties.TestingRun(t)
+
+	if IntIntersection() != nil {
+		t.Error("intersection of no slices should be nil")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	numSliceGenerator := gen.SliceOf(gen.IntRange(0, 20))
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("validate accepts sorted slice", prop.ForAll(func(input []int) bool {
+		IntSort(input)
+		return IntValidate(input) == nil
+	}, numSliceGenerator))
+
+	properties.Property("validate rejects unsorted slice", prop.ForAll(func(input []int) bool {
+		sort.Sort(sort.Reverse(sort.IntSlice(input)))
+		input = append([]int{1}, append(input, 0)...)
+		return errors.Is(IntValidate(input), ErrIntComparatorContract)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestSafeAPI(t *testing.T) {
+	numberGenerator := gen.IntRange(0, 3)
+	// empty and single-element inputs are the most common source of panics
+	singleSliceGenerator := gen.SliceOfN(1, numberGenerator)
+	emptyOrSingle := gen.OneGenOf(gen.Const([]int{}), singleSliceGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("functions don't panic with empty or single-element input", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		if IntIndexOf(input, value) != -1 && input[0] != value {
+			return false
+		}
+		if IntContains(input, value) != (len(input) == 1 && input[0] == value) {
+			return false
+		}
+		_ = IntRemove(append([]int{}, input...), value)
+		_ = IntInsert(append([]int{}, input...), value)
+		return true
+	}, emptyOrSingle, numberGenerator))
+
+	properties.Property("safe functions return errors", prop.ForAll(func(input []int, value, index int) bool {
+		IntSort(input)
+		found := len(input) == 1 && input[0] == value
+		i, err := IntSafeBinarySearch(input, value)
+		if (len(input) == 0) != errors.Is(err, ErrIntEmpty) || (err == nil && i != 0) {
+			return false
+		}
+		i, err = IntSafeIndexOf(input, value)
+		if found != (err == nil) || (!found && (i != -1 || !errors.Is(err, ErrIntNotFound))) {
+			return false
+		}
+		removed, err := IntSafeRemove(append([]int{}, input...), value)
+		if found != (err == nil) || (found && len(removed) != 0) || (!found && !deepEqual(removed, input)) {
+			return false
+		}
+		inRange := index >= 0 && index < len(input)
+		removed, err = IntSafeRemoveAt(append([]int{}, input...), index)
+		if inRange != (err == nil) || (!inRange && !errors.Is(err, ErrIntOutOfRange)) || (inRange && len(removed) != 0) {
+			return false
+		}
+		removed, err = IntSafeRemoveIndexes(append([]int{}, input...), []int{index})
+		return inRange == (err == nil) && (inRange || (errors.Is(err, ErrIntOutOfRange) && deepEqual(removed, input)))
+	}, emptyOrSingle, numberGenerator, gen.IntRange(-1, 2)))
+
+	properties.TestingRun(t)
+}
+
+func patternInts(seed int64, pattern, length int) []int {
+	r := rand.New(rand.NewSource(seed))
+	result := make([]int, length)
+	for i := range result {
+		switch pattern {
+		case 0: // random
+			result[i] = r.Int() - r.Int()
+		case 1: // ascending
+			result[i] = i
+		case 2: // descending
+			result[i] = length - i
+		case 3: // few distinct values
+			result[i] = r.Intn(4)
+		case 4: // organ pipe
+			if i < length/2 {
+				result[i] = i
+			} else {
+				result[i] = length - i
+			}
+		case 5: // sawtooth
+			result[i] = i % 16
+		default: // almost sorted
+			result[i] = i
+			if r.Intn(50) == 0 {
+				result[i] = r.Intn(length)
+			}
+		}
+	}
+	return result
+}
+
+func TestPdqsortPatterns(t *testing.T) {
+	// long enough to use partitioning and pattern detection instead of insertion sort
+	lengthGenerator := gen.IntRange(0, 3000)
+	patternGenerator := gen.IntRange(0, 6)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("pdqsort returns same result as sort", prop.ForAll(func(seed int64, pattern, length int) bool {
+		input := patternInts(seed, pattern, length)
+		expected := make([]int, len(input))
+		copy(expected, input)
+		sort.Ints(expected)
+		return IntSort(input) == nil && deepEqual(input, expected)
+	}, gen.Int64(), patternGenerator, lengthGenerator))
+
+	properties.Property("heap sort fallback returns same result as sort", prop.ForAll(func(seed int64, pattern, length int) bool {
+		input := patternInts(seed, pattern, length)
+		expected := make([]int, len(input))
+		copy(expected, input)
+		sort.Ints(expected)
+		heapSortInt(input, 0, len(input))
+		return deepEqual(input, expected)
+	}, gen.Int64(), patternGenerator, lengthGenerator))
+
+	properties.TestingRun(t)
+}
+
+func benchmarkSort(b *testing.B, sortFunc func(a []int)) {
+	input := patternInts(1, 0, 1000000)
+	a := make([]int, len(input))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(a, input)
+		sortFunc(a)
+	}
+}
+
+func BenchmarkPdqsort(b *testing.B) { benchmarkSort(b, func(a []int) { IntSort(a) }) }
+func BenchmarkSortSlice(b *testing.B) {
+	benchmarkSort(b, func(a []int) { sort.Slice(a, func(i, j int) bool { return a[i] < a[j] }) })
+}
+
+func TestIntersectionManySources(t *testing.T) {
+	numSliceGenerator := gen.SliceOf(gen.IntRange(0, 8))
+
+	properties := gopter.NewProperties(nil)
+
+	for _, count := range []int{3, 20} {
+		properties.Property(fmt.Sprintf("intersection of %d slices keeps items of the shortest slice in all slices", count), prop.ForAll(func(sources [][]int) bool {
+			shortest := 0
+			for i, src := range sources {
+				IntSort(src)
+				if len(src) < len(sources[shortest]) {
+					shortest = i
+				}
+			}
+			var expected []int
+			for _, item := range sources[shortest] {
+				found := true
+				for _, src := range sources {
+					if countInts(src)[item] == 0 {
+						found = false
+					}
+				}
+				if found {
+					expected = append(expected, item)
+				}
+			}
+			ok := true
+			result := IntIntersectionFunc(func(item int, indexes []int) {
+				for i, src := range sources {
+					if src[indexes[i]] != item || (indexes[i] > 0 && src[indexes[i]-1] == item && i != shortest) {
+						ok = false
+					}
+				}
+			}, sources...)
+			return ok && deepEqual(result, expected)
+		}, gen.SliceOfN(count, numSliceGenerator)))
+	}
+
+	properties.TestingRun(t)
+}
+
+func TestSafeSearchRangeAndSetOperations(t *testing.T) {
+	numSliceGenerator := gen.SliceOf(gen.IntRange(0, 10))
+	numberGenerator := gen.IntRange(-1, 11)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("safe floor, ceiling, lower and higher return ErrIntNotFound instead of false", prop.ForAll(func(input []int, value int) bool {
+		IntSort(input)
+		match := func(i int, ok bool, j int, err error) bool {
+			if ok {
+				return err == nil && i == j
+			}
+			return errors.Is(err, ErrIntNotFound) && j == -1
+		}
+		i, ok := IntFloor(input, value)
+		j, err := IntSafeFloor(input, value)
+		if !match(i, ok, j, err) {
+			return false
+		}
+		i, ok = IntCeiling(input, value)
+		j, err = IntSafeCeiling(input, value)
+		if !match(i, ok, j, err) {
+			return false
+		}
+		i, ok = IntLower(input, value)
+		j, err = IntSafeLower(input, value)
+		if !match(i, ok, j, err) {
+			return false
+		}
+		i, ok = IntHigher(input, value)
+		j, err = IntSafeHigher(input, value)
+		return match(i, ok, j, err)
+	}, numSliceGenerator, numberGenerator))
+
+	properties.Property("safe range functions reject reversed range", prop.ForAll(func(input []int, from, to int) bool {
+		IntSort(input)
+		reversed := to < from
+		items, err := IntSafeRange(input, from, to)
+		if reversed != errors.Is(err, ErrIntInvalidRange) || (!reversed && !deepEqual(items, IntRange(input, from, to))) {
+			return false
+		}
+		items, err = IntSafeRangeInclusive(input, from, to)
+		if reversed != errors.Is(err, ErrIntInvalidRange) || (!reversed && !deepEqual(items, IntRangeInclusive(input, from, to))) {
+			return false
+		}
+		expected := input
+		if !reversed {
+			expected = IntRemoveRange(append([]int{}, input...), from, to)
+		}
+		items, err = IntSafeRemoveRange(append([]int{}, input...), from, to)
+		if reversed != errors.Is(err, ErrIntInvalidRange) || !deepEqual(items, expected) {
+			return false
+		}
+		expected = input
+		if !reversed {
+			expected = IntRemoveRangeInclusive(append([]int{}, input...), from, to)
+		}
+		items, err = IntSafeRemoveRangeInclusive(append([]int{}, input...), from, to)
+		return reversed == errors.Is(err, ErrIntInvalidRange) && deepEqual(items, expected)
+	}, numSliceGenerator, numberGenerator, numberGenerator))
+
+	properties.Property("safe insert and set operations reject unsorted slices", prop.ForAll(func(input1, input2 []int, value int) bool {
+		IntSort(input1)
+		IntSort(input2)
+		unsorted := append([]int{1}, append(append([]int{}, input1...), 0)...)
+		items, err := IntSafeInsert(append([]int{}, input1...), value)
+		if err != nil || !deepEqual(items, IntInsert(append([]int{}, input1...), value)) {
+			return false
+		}
+		if items, err = IntSafeInsert(unsorted, value); !errors.Is(err, ErrIntComparatorContract) || !deepEqual(items, unsorted) {
+			return false
+		}
+		if items, err = IntSafeUnion(input1, input2); err != nil || !deepEqual(items, IntUnion(input1, input2)) {
+			return false
+		}
+		if _, err = IntSafeUnion(input1, unsorted); !errors.Is(err, ErrIntComparatorContract) {
+			return false
+		}
+		if items, err = IntSafeIntersection(input1, input2); err != nil || !deepEqual(items, IntIntersection(input1, input2)) {
+			return false
+		}
+		if _, err = IntSafeIntersection(unsorted, input2); !errors.Is(err, ErrIntComparatorContract) {
+			return false
+		}
+		if items, err = IntSafeDifference(input1, input2); err != nil || !deepEqual(items, IntDifference(input1, input2)) {
+			return false
+		}
+		if _, err = IntSafeDifference(input1, unsorted); !errors.Is(err, ErrIntComparatorContract) {
+			return false
+		}
+		if items, err = IntSafeSymmetricDifference(input1, input2); err != nil || !deepEqual(items, IntSymmetricDifference(input1, input2)) {
+			return false
+		}
+		_, err = IntSafeSymmetricDifference(unsorted, input2)
+		return errors.Is(err, ErrIntComparatorContract)
+	}, numSliceGenerator, numSliceGenerator, numberGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRadixSort(t *testing.T) {
+	// long enough to be sorted by radix sort instead of falling back to pdqsort
+	lengthGenerator := gen.IntRange(0, 2000)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("radix sort of int returns same result as sort", prop.ForAll(func(seed int64, length int) bool {
+		r := rand.New(rand.NewSource(seed))
+		input := make([]int, length)
+		for i := range input {
+			input[i] = int(r.Uint64())
+			if i%3 == 0 {
+				input[i] = r.Intn(1000) - 500
+			}
+		}
+		expected := make([]int, len(input))
+		copy(expected, input)
+		IntSort(expected)
+		return IntRadixSort(input) == nil && deepEqual(input, expected)
+	}, gen.Int64(), lengthGenerator))
+
+	properties.Property("radix sort of named integer type returns same result as sort", prop.ForAll(func(seed int64, length int) bool {
+		r := rand.New(rand.NewSource(seed))
+		input := make([]ID, length)
+		for i := range input {
+			input[i] = ID(r.Uint32())
+		}
+		expected := make([]ID, len(input))
+		copy(expected, input)
+		IDSort(expected)
+		return IDRadixSort(input) == nil && reflect.DeepEqual(input, expected)
+	}, gen.Int64(), lengthGenerator))
+
+	properties.TestingRun(t)
+}
@@ -0,0 +1,4 @@
+package comparablepdqsort
+
+// ID is a named integer type. slicesgen -radix resolves it to int32.
+type ID int32
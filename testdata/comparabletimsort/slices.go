@@ -1,15 +1,25 @@
-// This file was automatically generated by genny.
-// Any changes will be lost if this file is regenerated.
-// see https://github.com/cheekybits/genny
+// Code generated by slicesgen. DO NOT EDIT.
 
 package comparable
 
 import (
 	"errors"
 	"fmt"
+
+	"runtime"
 	"sort"
+	"sync"
+	"unsafe"
 )
 
+// ErrIntComparatorContract is returned when items can't be ordered by < (for example, an item is NaN
+// that is not equal to itself or the sort result is not in order). Use errors.Is to check it.
+var ErrIntComparatorContract = errors.New("comparison method violates its general contract")
+
+// ErrIntInternalInvariant is returned when TimSort detects a broken internal invariant in the middle of sort.
+// It is usually caused by a comparator that returns inconsistent results. Use errors.Is to check it.
+var ErrIntInternalInvariant = errors.New("internal invariant of sort is broken")
+
 // Package timsort provides fast stable sort, uses external comparator.
 //
 // A stable, adaptive, iterative mergesort that requires far fewer than
@@ -100,13 +110,12 @@ type timSortHandler struct {
 }
 
 /**
- * Creates a TimSort instance to maintain the state of an ongoing sort.
+ * Prepares the TimSort instance to maintain the state of an ongoing sort.
+ * Buffers of the previous sort are reused if they are large enough.
  *
  * @param a the array to be sorted
  */
-func newTimSort(a []int) (h *timSortHandler) {
-	h = new(timSortHandler)
-
+func (h *timSortHandler) init(a []int) {
 	h.a = a
 	h.minGallop = minGallop
 	h.stackSize = 0
@@ -119,7 +128,9 @@ func newTimSort(a []int) (h *timSortHandler) {
 		tmpSize = len / 2
 	}
 
-	h.tmp = make([]int, tmpSize)
+	if cap(h.tmp) < tmpSize {
+		h.tmp = make([]int, tmpSize)
+	}
 
 	/*
 	 * Allocate runs-to-be-merged stack (which cannot be expanded).  The
@@ -142,14 +153,23 @@ func newTimSort(a []int) (h *timSortHandler) {
 		stackLen = 19
 	}
 
-	h.runBase = make([]int, stackLen)
-	h.runLen = make([]int, stackLen)
-
-	return h
+	if cap(h.runBase) < stackLen {
+		h.runBase = make([]int, stackLen)
+		h.runLen = make([]int, stackLen)
+	}
 }
 
 // IntSort sorts an array using the provided comparator
 func IntSort(a []int) (err error) {
+	var h timSortHandler
+	if err := h.sort(a); err != nil {
+		return err
+	}
+	return checkSortedInt(a)
+}
+
+// sort sorts a with TimSort. It keeps buffers in h for the next sort.
+func (h *timSortHandler) sort(a []int) (err error) {
 	lo := 0
 	hi := len(a)
 	nRemaining := hi
@@ -174,7 +194,7 @@ func IntSort(a []int) (err error) {
 	 * to maintain stack invariant.
 	 */
 
-	ts := newTimSort(a)
+	h.init(a)
 	minRun, err := minRunLength(nRemaining)
 	if err != nil {
 		return
@@ -199,8 +219,8 @@ func IntSort(a []int) (err error) {
 		}
 
 		// Push run onto pending-run stack, and maybe merge
-		ts.pushRun(lo, runLen)
-		if err = ts.mergeCollapse(); err != nil {
+		h.pushRun(lo, runLen)
+		if err = h.mergeCollapse(); err != nil {
 			return err
 		}
 
@@ -214,18 +234,196 @@ func IntSort(a []int) (err error) {
 
 	// Merge all remaining runs to complete sort
 	if lo != hi {
-		return errors.New("lo must equal hi")
+		return fmt.Errorf("%w: lo must equal hi", ErrIntInternalInvariant)
 	}
 
-	if err = ts.mergeForceCollapse(); err != nil {
+	if err = h.mergeForceCollapse(); err != nil {
 		return
 	}
-	if ts.stackSize != 1 {
-		return errors.New("ts.stackSize != 1")
+	if h.stackSize != 1 {
+		return fmt.Errorf("%w: h.stackSize != 1", ErrIntInternalInvariant)
 	}
 	return
 }
 
+// IntSorter sorts slices with TimSort like IntSort, but keeps its temporary buffers to reuse them
+// in the next sort. It avoids allocation when sorting many slices.
+//
+// A IntSorter is not safe for concurrent use. To share sorters between goroutines, keep them in sync.Pool
+// and call Reset before putting them back.
+type IntSorter struct {
+	h timSortHandler
+}
+
+// NewIntSorter returns a IntSorter.
+func NewIntSorter() *IntSorter {
+	return &IntSorter{}
+}
+
+// Sort sorts an array. It returns the same errors as IntSort.
+func (s *IntSorter) Sort(a []int) error {
+	err := s.h.sort(a)
+	s.h.a = nil
+	if err != nil {
+		return err
+	}
+	return checkSortedInt(a)
+}
+
+// Reset clears the temporary buffer so that it doesn't keep items alive. It keeps the capacity of the buffer.
+func (s *IntSorter) Reset() {
+	var zero int
+	for i := range s.h.tmp {
+		s.h.tmp[i] = zero
+	}
+	s.h.a = nil
+}
+
+// parallelSortMinLengthInt is the minimum length of a chunk that IntParallelSort sorts or merges
+// in its own goroutine. Shorter slices are sorted by IntSort.
+const parallelSortMinLengthInt = 1 << 12
+
+// IntParallelSort sorts an array in ascendant order with workers goroutines.
+// If workers is zero or negative, it uses runtime.GOMAXPROCS(0).
+//
+// It splits the array into chunks, sorts each chunk by IntSort concurrently and merges them in parallel.
+// It is stable like IntSort and returns the same errors, checking the order after the last merge.
+// It allocates a buffer as large as the array.
+func IntParallelSort(a []int, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxWorkers := len(a) / parallelSortMinLengthInt; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers < 2 {
+		return IntSort(a)
+	}
+	bounds := make([]int, workers+1)
+	for i := range bounds {
+		bounds[i] = len(a) * i / workers
+	}
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = IntSort(a[bounds[i]:bounds[i+1]])
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	// merge neighbor runs pairwise until one run remains, switching source and destination each round
+	src, dst := a, make([]int, len(a))
+	for runs := len(bounds) - 1; runs > 1; runs = len(bounds) - 1 {
+		parts := workers / (runs / 2)
+		next := make([]int, 0, runs/2+2)
+		for i := 0; i < runs; i += 2 {
+			lo := bounds[i]
+			next = append(next, lo)
+			if i+1 == runs {
+				copy(dst[lo:], src[lo:bounds[i+1]])
+				continue
+			}
+			mid, hi := bounds[i+1], bounds[i+2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				parallelMergeInt(src[lo:mid], src[mid:hi], dst[lo:hi], parts)
+			}()
+		}
+		wg.Wait()
+		bounds = append(next, len(a))
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+	// each chunk passed the check of IntSort, but a comparator that is inconsistent between chunks
+	// can still leave the merged result out of order
+	return checkSortedInt(a)
+}
+
+// parallelMergeInt merges sorted slices a and b into dst stably. It splits the merge into at most parts goroutines.
+func parallelMergeInt(a, b, dst []int, parts int) {
+	if parts < 2 || len(a)+len(b) < 2*parallelSortMinLengthInt {
+		mergeInt(a, b, dst)
+		return
+	}
+	// split the longer slice at the middle. Items of a that are equal to items of b stay in front of them.
+	var i, j int
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		j = IntLowerBound(b, a[i])
+	} else {
+		j = len(b) / 2
+		i = IntUpperBound(a, b[j])
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMergeInt(a[:i], b[:j], dst[:i+j], parts/2)
+	}()
+	parallelMergeInt(a[i:], b[j:], dst[i+j:], parts-parts/2)
+	wg.Wait()
+}
+
+// mergeInt merges sorted slices a and b into dst stably.
+func mergeInt(a, b, dst []int) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if b[j] < a[i] {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// checkSortedInt returns an error that wraps ErrIntComparatorContract if a is not in order after sort.
+// TimSort doesn't detect all broken comparators by its internal invariants, so IntSort checks the result at last.
+func checkSortedInt(a []int) error {
+	for i := range a {
+		if a[i] != a[i] {
+			return fmt.Errorf("%w: item is not equal to itself at %d after sort", ErrIntComparatorContract, i)
+		}
+		if i > 0 && a[i] < a[i-1] {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrIntComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// IntValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If IntSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrIntComparatorContract.
+func IntValidate(sorted []int) error {
+	for i, item := range sorted {
+		if item != item {
+			return fmt.Errorf("%w: item is not equal to itself at %d", ErrIntComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if item < prev {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrIntComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
 /**
  * Sorts the specified portion of the specified array using a binary
  * insertion sort.  This is the best method for sorting small numbers
@@ -246,7 +444,7 @@ func IntSort(a []int) (err error) {
  */
 func binarySort(a []int, lo, hi, start int) (err error) {
 	if lo > start || start > hi {
-		return errors.New("lo <= start && start <= hi")
+		return fmt.Errorf("%w: lo <= start && start <= hi", ErrIntInternalInvariant)
 	}
 
 	if start == lo {
@@ -261,7 +459,7 @@ func binarySort(a []int, lo, hi, start int) (err error) {
 		right := start
 
 		if left > right {
-			return errors.New("left <= right")
+			return fmt.Errorf("%w: left <= right", ErrIntInternalInvariant)
 		}
 
 		/*
@@ -279,7 +477,7 @@ func binarySort(a []int, lo, hi, start int) (err error) {
 		}
 
 		if left != right {
-			return errors.New("left == right")
+			return fmt.Errorf("%w: left == right", ErrIntInternalInvariant)
 		}
 
 		/*
@@ -306,35 +504,36 @@ func binarySort(a []int, lo, hi, start int) (err error) {
 	return
 }
 
-/**
-  * Returns the length of the run beginning at the specified position in
-  * the specified array and reverses the run if it is descending (ensuring
-  * that the run will always be ascending when the method returns).
-  *
-  * A run is the longest ascending sequence with:
-  *
-  *    a[lo] <= a[lo + 1] <= a[lo + 2] <= ...
-  *
-  * or the longest descending sequence with:
-  *
-  *    a[lo] >  a[lo + 1] >  a[lo + 2] >  ...
-  *
-  * For its intended use in a stable mergesort, the strictness of the
-  * definition of "descending" is needed so that the call can safely
-  * reverse a descending sequence without violating stability.
-  *
-  * @param a the array in which a run is to be counted and possibly reversed
-  * @param lo index of the first element in the run
-  * @param hi index after the last element that may be contained in the run.
-           It is required that @code{lo < hi}.
-  * @param c the comparator to used for the sort
-  * @return  the length of the run beginning at the specified position in
-  *          the specified array
+/*
+*
+  - Returns the length of the run beginning at the specified position in
+  - the specified array and reverses the run if it is descending (ensuring
+  - that the run will always be ascending when the method returns).
+    *
+  - A run is the longest ascending sequence with:
+    *
+  - a[lo] <= a[lo + 1] <= a[lo + 2] <= ...
+    *
+  - or the longest descending sequence with:
+    *
+  - a[lo] >  a[lo + 1] >  a[lo + 2] >  ...
+    *
+  - For its intended use in a stable mergesort, the strictness of the
+  - definition of "descending" is needed so that the call can safely
+  - reverse a descending sequence without violating stability.
+    *
+  - @param a the array in which a run is to be counted and possibly reversed
+  - @param lo index of the first element in the run
+  - @param hi index after the last element that may be contained in the run.
+    It is required that @code{lo < hi}.
+  - @param c the comparator to used for the sort
+  - @return  the length of the run beginning at the specified position in
+  - the specified array
 */
 func countRunAndMakeAscending(a []int, lo, hi int) (int, error) {
 
 	if lo >= hi {
-		return 0, errors.New("lo < hi")
+		return 0, fmt.Errorf("%w: lo < hi", ErrIntInternalInvariant)
 	}
 
 	runHi := lo + 1
@@ -394,7 +593,7 @@ func reverseRange(a []int, lo, hi int) {
  */
 func minRunLength(n int) (int, error) {
 	if n < 0 {
-		return 0, errors.New("n >= 0")
+		return 0, fmt.Errorf("%w: n >= 0", ErrIntInternalInvariant)
 	}
 	r := 0 // Becomes 1 if any 1 bits are shifted off
 	for n >= minMerge {
@@ -475,15 +674,15 @@ func (h *timSortHandler) mergeForceCollapse() (err error) {
  */
 func (h *timSortHandler) mergeAt(i int) (err error) {
 	if h.stackSize < 2 {
-		return errors.New("stackSize >= 2")
+		return fmt.Errorf("%w: stackSize >= 2", ErrIntInternalInvariant)
 	}
 
 	if i < 0 {
-		return errors.New(" i >= 0")
+		return fmt.Errorf("%w: i >= 0", ErrIntInternalInvariant)
 	}
 
 	if i != h.stackSize-2 && i != h.stackSize-3 {
-		return errors.New("if i == stackSize - 2 || i == stackSize - 3")
+		return fmt.Errorf("%w: if i == stackSize - 2 || i == stackSize - 3", ErrIntInternalInvariant)
 	}
 
 	base1 := h.runBase[i]
@@ -492,11 +691,11 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 	len2 := h.runLen[i+1]
 
 	if len1 <= 0 || len2 <= 0 {
-		return errors.New("len1 > 0 && len2 > 0")
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0", ErrIntInternalInvariant)
 	}
 
 	if base1+len1 != base2 {
-		return errors.New("base1 + len1 == base2")
+		return fmt.Errorf("%w: base1 + len1 == base2", ErrIntInternalInvariant)
 	}
 
 	/*
@@ -520,7 +719,7 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 		return err
 	}
 	if k < 0 {
-		return errors.New(" k >= 0;")
+		return fmt.Errorf("%w: k >= 0", ErrIntInternalInvariant)
 	}
 	base1 += k
 	len1 -= k
@@ -537,7 +736,7 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 		return
 	}
 	if len2 < 0 {
-		return errors.New(" len2 >= 0;")
+		return fmt.Errorf("%w: len2 >= 0", ErrIntInternalInvariant)
 	}
 	if len2 == 0 {
 		return
@@ -547,12 +746,12 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
 	if len1 <= len2 {
 		err = h.mergeLo(base1, len1, base2, len2)
 		if err != nil {
-			return fmt.Errorf("mergeLo: %v", err)
+			return fmt.Errorf("mergeLo: %w", err)
 		}
 	} else {
 		err = h.mergeHi(base1, len1, base2, len2)
 		if err != nil {
-			return fmt.Errorf("mergeHi: %v", err)
+			return fmt.Errorf("mergeHi: %w", err)
 		}
 	}
 	return
@@ -577,7 +776,7 @@ func (h *timSortHandler) mergeAt(i int) (err error) {
  */
 func gallopLeft(key int, a []int, base, len, hint int) (int, error) {
 	if len <= 0 || hint < 0 || hint >= len {
-		return 0, errors.New(" len > 0 && hint >= 0 && hint < len;")
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrIntInternalInvariant)
 	}
 	lastOfs := 0
 	ofs := 1
@@ -620,7 +819,7 @@ func gallopLeft(key int, a []int, base, len, hint int) (int, error) {
 	}
 
 	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
-		return 0, errors.New(" -1 <= lastOfs && lastOfs < ofs && ofs <= len;")
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrIntInternalInvariant)
 	}
 
 	/*
@@ -640,7 +839,7 @@ func gallopLeft(key int, a []int, base, len, hint int) (int, error) {
 	}
 
 	if lastOfs != ofs {
-		return 0, errors.New(" lastOfs == ofs") // so a[base + ofs - 1] < key <= a[base + ofs]
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrIntInternalInvariant) // so a[base + ofs - 1] < key <= a[base + ofs]
 	}
 	return ofs, nil
 }
@@ -659,7 +858,7 @@ func gallopLeft(key int, a []int, base, len, hint int) (int, error) {
  */
 func gallopRight(key int, a []int, base, len, hint int) (int, error) {
 	if len <= 0 || hint < 0 || hint >= len {
-		return 0, errors.New(" len > 0 && hint >= 0 && hint < len;")
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrIntInternalInvariant)
 	}
 
 	ofs := 1
@@ -701,7 +900,7 @@ func gallopRight(key int, a []int, base, len, hint int) (int, error) {
 		ofs += hint
 	}
 	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
-		return 0, errors.New("-1 <= lastOfs && lastOfs < ofs && ofs <= len")
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrIntInternalInvariant)
 	}
 
 	/*
@@ -720,7 +919,7 @@ func gallopRight(key int, a []int, base, len, hint int) (int, error) {
 		}
 	}
 	if lastOfs != ofs {
-		return 0, errors.New(" lastOfs == ofs") // so a[b + ofs - 1] <= key < a[b + ofs]
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrIntInternalInvariant) // so a[b + ofs - 1] <= key < a[b + ofs]
 	}
 	return ofs, nil
 }
@@ -743,7 +942,7 @@ func gallopRight(key int, a []int, base, len, hint int) (int, error) {
  */
 func (h *timSortHandler) mergeLo(base1, len1, base2, len2 int) (err error) {
 	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
-		return errors.New(" len1 > 0 && len2 > 0 && base1 + len1 == base2")
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrIntInternalInvariant)
 	}
 
 	// Copy first run into temp array
@@ -784,7 +983,7 @@ outer:
 		 */
 		for {
 			if len1 <= 1 || len2 <= 0 {
-				return errors.New(" len1 > 1 && len2 > 0")
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrIntInternalInvariant)
 			}
 
 			if a[cursor2] < tmp[cursor1] {
@@ -820,7 +1019,7 @@ outer:
 		 */
 		for {
 			if len1 <= 1 || len2 <= 0 {
-				return errors.New("len1 > 1 && len2 > 0")
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrIntInternalInvariant)
 			}
 			count1, err = gallopRight(a[cursor2], tmp, cursor1, len1, 0)
 			if err != nil {
@@ -882,18 +1081,18 @@ outer:
 	if len1 == 1 {
 
 		if len2 <= 0 {
-			return errors.New(" len2 > 0;")
+			return fmt.Errorf("%w: len2 > 0", ErrIntInternalInvariant)
 		}
 		copy(a[dest:dest+len2], a[cursor2:cursor2+len2])
 		a[dest+len2] = tmp[cursor1] //  Last elt of run 1 to end of merge
 	} else if len1 == 0 {
-		return errors.New("comparison method violates its general contract")
+		return ErrIntComparatorContract
 	} else {
 		if len2 != 0 {
-			return errors.New("len2 == 0;")
+			return fmt.Errorf("%w: len2 == 0", ErrIntInternalInvariant)
 		}
 		if len1 <= 1 {
-			return errors.New(" len1 > 1;")
+			return fmt.Errorf("%w: len1 > 1", ErrIntInternalInvariant)
 		}
 
 		copy(a[dest:dest+len1], tmp[cursor1:cursor1+len1])
@@ -914,7 +1113,7 @@ outer:
  */
 func (h *timSortHandler) mergeHi(base1, len1, base2, len2 int) (err error) {
 	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
-		return errors.New("len1 > 0 && len2 > 0 && base1 + len1 == base2;")
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrIntInternalInvariant)
 	}
 
 	// Copy second run into temp array
@@ -958,7 +1157,7 @@ outer:
 		 */
 		for {
 			if len1 <= 0 || len2 <= 1 {
-				return errors.New(" len1 > 0 && len2 > 1;")
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrIntInternalInvariant)
 			}
 			if tmp[cursor2] < a[cursor1] {
 				a[dest] = a[cursor1]
@@ -993,7 +1192,7 @@ outer:
 		 */
 		for {
 			if len1 <= 0 || len2 <= 1 {
-				return errors.New(" len1 > 0 && len2 > 1;")
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrIntInternalInvariant)
 			}
 			if gr, err := gallopRight(tmp[cursor2], a, base1, len1, len1-1); err == nil {
 				count1 = len1 - gr
@@ -1058,7 +1257,7 @@ outer:
 
 	if len2 == 1 {
 		if len1 <= 0 {
-			return errors.New(" len1 > 0;")
+			return fmt.Errorf("%w: len1 > 0", ErrIntInternalInvariant)
 		}
 		dest -= len1
 		cursor1 -= len1
@@ -1066,14 +1265,14 @@ outer:
 		copy(a[dest+1:dest+1+len1], a[cursor1+1:cursor1+1+len1])
 		a[dest] = tmp[cursor2] // Move first elt of run2 to front of merge
 	} else if len2 == 0 {
-		return errors.New("comparison method violates its general contract")
+		return ErrIntComparatorContract
 	} else {
 		if len1 != 0 {
-			return errors.New("len1 == 0;")
+			return fmt.Errorf("%w: len1 == 0", ErrIntInternalInvariant)
 		}
 
 		if len2 <= 0 {
-			return errors.New(" len2 > 0;")
+			return fmt.Errorf("%w: len2 > 0", ErrIntInternalInvariant)
 		}
 
 		copy(a[dest-(len2-1):dest+1], tmp)
@@ -1133,8 +1332,164 @@ func IntBinarySearch(sorted []int, item int) int {
 	return i
 }
 
+// IntLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func IntLowerBound(sorted []int, item int) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if sorted[h] < item {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// IntUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func IntUpperBound(sorted []int, item int) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if !(item < sorted[h]) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopInt returns first index i that satisfies !(sorted[i] < item) like IntLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopInt(sorted []int, item int) int {
+	if len(sorted) == 0 || !(sorted[0] < item) {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && sorted[hi] < item {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + IntLowerBound(sorted[lo+1:hi], item)
+}
+
+// IntEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func IntEqualRange(sorted []int, item int) (lo, hi int) {
+	lo = IntLowerBound(sorted, item)
+	hi = lo + IntUpperBound(sorted[lo:], item)
+	return lo, hi
+}
+
+// IntCount returns the number of items that are equal to item.
+func IntCount(sorted []int, item int) int {
+	lo, hi := IntEqualRange(sorted, item)
+	return hi - lo
+}
+
+// IntFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func IntFloor(sorted []int, item int) (int, bool) {
+	i := IntUpperBound(sorted, item)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// IntCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func IntCeiling(sorted []int, item int) (int, bool) {
+	i := IntLowerBound(sorted, item)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// IntLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func IntLower(sorted []int, item int) (int, bool) {
+	i := IntLowerBound(sorted, item)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// IntHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func IntHigher(sorted []int, item int) (int, bool) {
+	i := IntUpperBound(sorted, item)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// IntRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func IntRangeIndexes(sorted []int, from, to int, inclusive bool) (lo, hi int) {
+	lo = IntLowerBound(sorted, from)
+	if inclusive {
+		hi = lo + IntUpperBound(sorted[lo:], to)
+	} else {
+		hi = lo + IntLowerBound(sorted[lo:], to)
+	}
+	return lo, hi
+}
+
+// IntRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func IntRange(sorted []int, from, to int) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false)
+	return sorted[lo:hi:hi]
+}
+
+// IntRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like IntRange.
+func IntRangeInclusive(sorted []int, from, to int) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true)
+	return sorted[lo:hi:hi]
+}
+
+// IntRemoveRange removes items in [from, to) and returns a sorted slice.
+func IntRemoveRange(sorted []int, from, to int) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// IntRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func IntRemoveRangeInclusive(sorted []int, from, to int) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// IntCountRange returns the number of items in [from, to).
+func IntCountRange(sorted []int, from, to int) int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false)
+	return hi - lo
+}
+
+// IntCountRangeInclusive returns the number of items in [from, to].
+func IntCountRangeInclusive(sorted []int, from, to int) int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true)
+	return hi - lo
+}
+
 // IntIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
 func IntIndexOf(sorted []int, item int) int {
+	if len(sorted) == 0 {
+		return -1
+	}
 	i := IntBinarySearch(sorted, item)
 	if sorted[i] == item {
 		return i
@@ -1144,6 +1499,9 @@ func IntIndexOf(sorted []int, item int) int {
 
 // IntContains returns true if item is in a sorted slice. Otherwise false.
 func IntContains(sorted []int, item int) bool {
+	if len(sorted) == 0 {
+		return false
+	}
 	i := IntBinarySearch(sorted, item)
 	return sorted[i] == item
 }
@@ -1157,8 +1515,63 @@ func IntInsert(sorted []int, item int) []int {
 	return append(sorted[:i], append([]int{item}, sorted[i:]...)...)
 }
 
+// IntInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling IntInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func IntInsertAll(sorted []int, items []int) []int {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]int, len(items))
+	copy(batch, items)
+	IntSort(batch)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && batch[j] < sorted[i] {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]int, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if batch[j] < sorted[i] {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// IntInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func IntInsertUnique(sorted []int, item int) ([]int, bool) {
+	i := IntLowerBound(sorted, item)
+	if i < len(sorted) && sorted[i] == item {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]int{item}, sorted[i:]...)...), true
+}
+
 // IntRemove removes item in a sorted slice.
 func IntRemove(sorted []int, item int) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
 	i := IntBinarySearch(sorted, item)
 	if sorted[i] == item {
 		return IntRemoveAt(sorted, i)
@@ -1171,54 +1584,186 @@ func IntRemoveAt(sorted []int, i int) []int {
 	return append(sorted[:i], sorted[i+1:]...)
 }
 
+// IntRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func IntRemoveAll(sorted []int, items []int) []int {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && items[j] < item {
+			j++
+		}
+		if j < len(items) && items[j] == item {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// IntRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func IntRemoveIf(sorted []int, pred func(item int) bool) []int {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IntRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func IntRemoveIndexes(sorted []int, indexes []int) []int {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// IntUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func IntUnique(sorted []int) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if result[len(result)-1] < item {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IntUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func IntUniqueFunc(sorted []int, equal func(a, b int) bool) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdInt is the number of source slices that IntIterateOver and IntUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdInt = 8
+
 // IntIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
 func IntIterateOver(callback func(item int, srcIndex int), sorted ...[]int) {
 	sourceSlices := make([][]int, 0, len(sorted))
-	for _, src := range sorted {
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
 		if len(src) > 0 {
 			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
 		}
 	}
-	sourceSliceCount := len(sourceSlices)
-	if sourceSliceCount == 0 {
-		return
-	} else if sourceSliceCount == 1 {
-		for i, value := range sourceSlices[0] {
-			callback(value, i)
-		}
-		return
+	if len(sourceSlices) > heapMergeThresholdInt {
+		iterateOverHeapInt(callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearInt(callback, sourceSlices, sliceIndex)
 	}
-	indexes := make([]int, sourceSliceCount)
-	sliceIndex := make([]int, sourceSliceCount)
-	for i := range sourceSlices {
-		sliceIndex[i] = i
+}
+
+// iterateOverLinearInt finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapInt when there are a few source slices.
+func iterateOverLinearInt(callback func(item int, srcIndex int), sourceSlices [][]int, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
 	}
-	index := 0
-	for {
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
 		minSlice := 0
 		minItem := sourceSlices[0][indexes[0]]
-		for i := 1; i < sourceSliceCount; i++ {
+		for i := 1; i < len(sourceSlices); i++ {
 			if sourceSlices[i][indexes[i]] < minItem {
 				minSlice = i
 				minItem = sourceSlices[i][indexes[i]]
 			}
 		}
 		callback(minItem, sliceIndex[minSlice])
-		index++
 		indexes[minSlice]++
 		if indexes[minSlice] == len(sourceSlices[minSlice]) {
 			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
 			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
 			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
-			sourceSliceCount--
-			if len(sourceSlices) == 1 {
-				slice := sourceSlices[0]
-				for i := indexes[0]; i < len(slice); i++ {
-					callback(slice[i], sliceIndex[0])
-				}
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapInt keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearInt takes O(N k).
+func iterateOverHeapInt(callback func(item int, srcIndex int), sourceSlices [][]int, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if x != y {
+			return x < y
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
 				return
 			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
 		}
+		down(0)
 	}
 }
 
@@ -1237,6 +1782,13 @@ func IntUnion(sorted ...[]int) []int {
 	} else if len(sourceSlices) == 1 {
 		return sourceSlices[0]
 	}
+	if len(sourceSlices) > heapMergeThresholdInt {
+		result := make([]int, 0, length)
+		IntIterateOver(func(item int, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
 	result := make([]int, length)
 	sourceSliceCount := len(sourceSlices)
 	indexes := make([]int, sourceSliceCount)
@@ -1265,7 +1817,46 @@ func IntUnion(sorted ...[]int) []int {
 	}
 }
 
+// IntUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func IntUnionDistinct(sorted ...[]int) []int {
+	var result []int
+	IntIterateOver(func(item int, srcIndex int) {
+		if len(result) == 0 || result[len(result)-1] < item {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// IntDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
 func IntDifference(sorted1, sorted2 []int) []int {
+	var result []int
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopInt(sorted1[i:], sorted2[j])
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopInt(sorted2[j:], sorted1[i])
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if sorted1[i] == sorted2[j] {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// IntSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func IntSymmetricDifference(sorted1, sorted2 []int) []int {
 	var result []int
 	var i, j int
 	for i < len(sorted1) && j < len(sorted2) {
@@ -1273,6 +1864,7 @@ func IntDifference(sorted1, sorted2 []int) []int {
 			result = append(result, sorted1[i])
 			i++
 		} else if sorted2[j] < sorted1[i] {
+			result = append(result, sorted2[j])
 			j++
 		} else {
 			i++
@@ -1280,49 +1872,793 @@ func IntDifference(sorted1, sorted2 []int) []int {
 		}
 	}
 	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
 	return result
 }
 
+// IntDifferenceAll creates difference group of base and all subtract slices and returns.
+func IntDifferenceAll(base []int, subtract ...[]int) []int {
+	return IntDifference(base, IntUnion(subtract...))
+}
+
+// IntDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func IntDiff(old, new []int) (added, removed, common []int) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		if old[i] < new[j] {
+			removed = append(removed, old[i])
+			i++
+		} else if new[j] < old[i] {
+			added = append(added, new[j])
+			j++
+		} else {
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// IntIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func IntIsSubset(sub, super []int) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopInt(super[j:], item)
+		if j == len(super) || item < super[j] {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// IntIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func IntIsSuperset(super, sub []int) bool {
+	return IntIsSubset(sub, super)
+}
+
+// IntIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func IntIsDisjoint(sorted1, sorted2 []int) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopInt(sorted1[i:], sorted2[j])
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopInt(sorted2[j:], sorted1[i])
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if sorted1[i] == sorted2[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// IntEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func IntEqual(sorted1, sorted2 []int) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if sorted1[i] != sorted2[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IntIntersectionSize returns the number of items that IntIntersection returns for sorted1 and sorted2
+// without allocating memory. Like IntIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func IntIntersectionSize(sorted1, sorted2 []int) int {
+	// IntIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopInt(long[j:], item)
+		if j == len(long) {
+			break
+		}
+		if item == long[j] {
+			count++
+		}
+	}
+	return count
+}
+
+// IntIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
 func IntIntersection(sorted ...[]int) []int {
-	sort.Slice(sorted, func(i, j int) bool {
-		return len(sorted[i]) < len(sorted[j])
+	return IntIntersectionFunc(nil, sorted...)
+}
+
+// IntIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func IntIntersectionFunc(callback func(item int, indexes []int), sorted ...[]int) []int {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
 	})
+	if len(sorted) > heapMergeThresholdInt {
+		return intersectionHeapInt(callback, sorted, order[0])
+	}
+	return intersectionLinearInt(callback, sorted, order)
+}
+
+// intersectionLinearInt checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapInt when there are a few source slices or the items are spread evenly.
+func intersectionLinearInt(callback func(item int, indexes []int), sorted [][]int, order []int) []int {
+	var result []int
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopInt(src[cursors[i]:], value)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if value < src[cursors[i]] {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapInt keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearInt when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapInt(callback func(item int, indexes []int), sorted [][]int, shortest int) []int {
 	var result []int
-	if len(sorted[0]) == 0 {
+	if len(sorted[shortest]) == 0 {
 		return result
 	}
 	cursors := make([]int, len(sorted))
-	terminate := false
-	for _, value := range sorted[0] {
-		needIncrement := false
-		for i := 1; i < len(sorted); i++ {
-			found := false
-			for j := cursors[i]; j < len(sorted[i]); j++ {
-				valueOfOtherSlice := sorted[i][cursors[i]]
-				if valueOfOtherSlice < value {
-					cursors[i] = j + 1
-				} else if value < valueOfOtherSlice {
-					needIncrement = true
-					break
-				} else {
-					found = true
-					break
-				}
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if maxItem < sorted[i][0] {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return sorted[a][cursors[a]] < sorted[b][cursors[b]]
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
 			}
-			if needIncrement {
-				break
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if sorted[src][cursors[src]] < maxItem {
+			cursors[src] += gallopInt(sorted[src][cursors[src]:], maxItem)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; maxItem < item {
+				maxItem = item
+			}
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && !(maxItem < s[cursors[shortest]]) {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
 			}
-			if !found {
-				terminate = true
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
 				break
 			}
 		}
-		if terminate {
-			break
+	}
+}
+
+// IntSortedSet is a set that keeps unique items in a sorted slice.
+type IntSortedSet struct {
+	items []int
+}
+
+// NewIntSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewIntSortedSet(items ...int) *IntSortedSet {
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	IntSort(sorted)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || unique[len(unique)-1] != item {
+			unique = append(unique, item)
 		}
-		if !needIncrement {
-			result = append(result, value)
+	}
+	return &IntSortedSet{items: unique}
+}
+
+// search returns first index i that satisfies item <= s.items[i]. If there is no such item, it returns s.Len().
+func (s *IntSortedSet) search(item int) int {
+	i, j := 0, len(s.items)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if s.items[h] < item {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *IntSortedSet) Add(item int) bool {
+	i := s.search(item)
+	if i < len(s.items) && s.items[i] == item {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *IntSortedSet) Delete(item int) bool {
+	i := s.search(item)
+	if i == len(s.items) || s.items[i] != item {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *IntSortedSet) Has(item int) bool {
+	i := s.search(item)
+	return i < len(s.items) && s.items[i] == item
+}
+
+// Len returns the number of items in the set.
+func (s *IntSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *IntSortedSet) At(i int) int {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *IntSortedSet) Range(callback func(i int, item int) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *IntSortedSet) Slice() []int {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *IntSortedSet) Union(other *IntSortedSet) *IntSortedSet {
+	result := make([]int, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.items[i] < other.items[j] {
+			result = append(result, s.items[i])
+			i++
+		} else if other.items[j] < s.items[i] {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &IntSortedSet{items: result}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *IntSortedSet) Intersect(other *IntSortedSet) *IntSortedSet {
+	var result []int
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.items[i] < other.items[j] {
+			i++
+		} else if other.items[j] < s.items[i] {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &IntSortedSet{items: result}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *IntSortedSet) Subtract(other *IntSortedSet) *IntSortedSet {
+	var result []int
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.items[i] < other.items[j] {
+			result = append(result, s.items[i])
+			i++
+		} else if other.items[j] < s.items[i] {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &IntSortedSet{items: result}
+}
+
+// IntSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type IntSortedMultiset struct {
+	items []int
+}
+
+// NewIntSortedMultiset creates a sorted multiset that has items.
+func NewIntSortedMultiset(items ...int) *IntSortedMultiset {
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	IntSort(sorted)
+	return &IntSortedMultiset{items: sorted}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *IntSortedMultiset) equalRange(item int) (lo, hi int) {
+	return IntEqualRange(m.items, item)
+}
+
+// Add adds item to the multiset.
+func (m *IntSortedMultiset) Add(item int) {
+	m.items = IntInsert(m.items, item)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *IntSortedMultiset) RemoveOne(item int) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = IntRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *IntSortedMultiset) RemoveAll(item int) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *IntSortedMultiset) Count(item int) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *IntSortedMultiset) Has(item int) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *IntSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *IntSortedMultiset) Distinct() []int {
+	var result []int
+	for i, item := range m.items {
+		if i == 0 || result[len(result)-1] != item {
+			result = append(result, item)
 		}
 	}
 	return result
 }
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *IntSortedMultiset) Range(callback func(item int, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && m.items[lo] == m.items[hi] {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *IntSortedMultiset) Slice() []int {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *IntSortedMultiset) Union(other *IntSortedMultiset) *IntSortedMultiset {
+	result := make([]int, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.items[i] < other.items[j] {
+			result = append(result, m.items[i])
+			i++
+		} else if other.items[j] < m.items[i] {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &IntSortedMultiset{items: result}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *IntSortedMultiset) Intersect(other *IntSortedMultiset) *IntSortedMultiset {
+	var result []int
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.items[i] < other.items[j] {
+			i++
+		} else if other.items[j] < m.items[i] {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &IntSortedMultiset{items: result}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *IntSortedMultiset) Subtract(other *IntSortedMultiset) *IntSortedMultiset {
+	var result []int
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.items[i] < other.items[j] {
+			result = append(result, m.items[i])
+			i++
+		} else if other.items[j] < m.items[i] {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &IntSortedMultiset{items: result}
+}
+
+// Safe API of the comparable-timsort template, appended by slicesgen -safe. Sorted inputs are checked
+// by IntValidate, which also rejects NaN because it is not equal to itself.
+
+// ErrIntEmpty is returned when a sorted slice is empty.
+var ErrIntEmpty = errors.New("slice is empty")
+
+// ErrIntNotFound is returned when an item is not in a sorted slice.
+var ErrIntNotFound = errors.New("item is not found")
+
+// ErrIntOutOfRange is returned when an index is out of range of a slice.
+var ErrIntOutOfRange = errors.New("index is out of range")
+
+// ErrIntInvalidRange is returned when the end of a range is less than its start.
+var ErrIntInvalidRange = errors.New("range is invalid")
+
+// IntSafeBinarySearch returns first index i that satisfies slices[i] <= item.
+// It returns ErrIntEmpty if a sorted slice is empty.
+func IntSafeBinarySearch(sorted []int, item int) (int, error) {
+	if len(sorted) == 0 {
+		return -1, ErrIntEmpty
+	}
+	return IntBinarySearch(sorted, item), nil
+}
+
+// IntSafeIndexOf returns index of item. It returns ErrIntNotFound if item is not in a sorted slice.
+func IntSafeIndexOf(sorted []int, item int) (int, error) {
+	i := IntIndexOf(sorted, item)
+	if i == -1 {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeRemove removes item in a sorted slice. It returns ErrIntNotFound if item is not in a sorted slice.
+func IntSafeRemove(sorted []int, item int) ([]int, error) {
+	i := IntIndexOf(sorted, item)
+	if i == -1 {
+		return sorted, ErrIntNotFound
+	}
+	return IntRemoveAt(sorted, i), nil
+}
+
+// IntSafeRemoveAt removes item at the specified index in a slice.
+// It returns an error that wraps ErrIntOutOfRange if the index is out of range.
+func IntSafeRemoveAt(sorted []int, i int) ([]int, error) {
+	if i < 0 || i >= len(sorted) {
+		return sorted, fmt.Errorf("%w: %d (length %d)", ErrIntOutOfRange, i, len(sorted))
+	}
+	return IntRemoveAt(sorted, i), nil
+}
+
+// IntSafeRemoveIndexes removes items at indexes from a sorted slice.
+// It returns an error that wraps ErrIntOutOfRange without modifying the slice if any index is out of range.
+func IntSafeRemoveIndexes(sorted []int, indexes []int) ([]int, error) {
+	for _, i := range indexes {
+		if i < 0 || i >= len(sorted) {
+			return sorted, fmt.Errorf("%w: %d (length %d)", ErrIntOutOfRange, i, len(sorted))
+		}
+	}
+	return IntRemoveIndexes(sorted, indexes), nil
+}
+
+// IntSafeFloor returns index of the greatest item that is less than or equal to item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeFloor(sorted []int, item int) (int, error) {
+	i, ok := IntFloor(sorted, item)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeCeiling returns index of the smallest item that is greater than or equal to item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeCeiling(sorted []int, item int) (int, error) {
+	i, ok := IntCeiling(sorted, item)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeLower returns index of the greatest item that is strictly less than item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeLower(sorted []int, item int) (int, error) {
+	i, ok := IntLower(sorted, item)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeHigher returns index of the smallest item that is strictly greater than item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeHigher(sorted []int, item int) (int, error) {
+	i, ok := IntHigher(sorted, item)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeRange returns items in [from, to) of a sorted slice like IntRange.
+// It returns an error that wraps ErrIntInvalidRange if to is less than from.
+func IntSafeRange(sorted []int, from, to int) ([]int, error) {
+	if to < from {
+		return nil, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRange(sorted, from, to), nil
+}
+
+// IntSafeRangeInclusive returns items in [from, to] of a sorted slice like IntRangeInclusive.
+// It returns an error that wraps ErrIntInvalidRange if to is less than from.
+func IntSafeRangeInclusive(sorted []int, from, to int) ([]int, error) {
+	if to < from {
+		return nil, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRangeInclusive(sorted, from, to), nil
+}
+
+// IntSafeRemoveRange removes items in [from, to) like IntRemoveRange.
+// It returns an error that wraps ErrIntInvalidRange without modifying the slice if to is less than from.
+func IntSafeRemoveRange(sorted []int, from, to int) ([]int, error) {
+	if to < from {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRemoveRange(sorted, from, to), nil
+}
+
+// IntSafeRemoveRangeInclusive removes items in [from, to] like IntRemoveRangeInclusive.
+// It returns an error that wraps ErrIntInvalidRange without modifying the slice if to is less than from.
+func IntSafeRemoveRangeInclusive(sorted []int, from, to int) ([]int, error) {
+	if to < from {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRemoveRangeInclusive(sorted, from, to), nil
+}
+
+// IntSafeInsert inserts item in a sorted slice like IntInsert. Insert into an unsorted slice
+// breaks the order silently, so it returns an error that wraps ErrIntComparatorContract without modifying the slice.
+func IntSafeInsert(sorted []int, item int) ([]int, error) {
+	if err := IntValidate(sorted); err != nil {
+		return sorted, err
+	}
+	return IntInsert(sorted, item), nil
+}
+
+// IntSafeUnion merges sorted slices like IntUnion.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeUnion(sorted ...[]int) ([]int, error) {
+	if err := validateSourcesInt(sorted); err != nil {
+		return nil, err
+	}
+	return IntUnion(sorted...), nil
+}
+
+// IntSafeIntersection returns common items of sorted slices like IntIntersection.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeIntersection(sorted ...[]int) ([]int, error) {
+	if err := validateSourcesInt(sorted); err != nil {
+		return nil, err
+	}
+	return IntIntersection(sorted...), nil
+}
+
+// IntSafeDifference returns items of sorted1 that are not in sorted2 like IntDifference.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeDifference(sorted1, sorted2 []int) ([]int, error) {
+	if err := validateSourcesInt([][]int{sorted1, sorted2}); err != nil {
+		return nil, err
+	}
+	return IntDifference(sorted1, sorted2), nil
+}
+
+// IntSafeSymmetricDifference returns items that are in only one of sorted slices like IntSymmetricDifference.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeSymmetricDifference(sorted1, sorted2 []int) ([]int, error) {
+	if err := validateSourcesInt([][]int{sorted1, sorted2}); err != nil {
+		return nil, err
+	}
+	return IntSymmetricDifference(sorted1, sorted2), nil
+}
+
+// validateSourcesInt validates each source slice of the set operations and tells which slice is broken.
+func validateSourcesInt(sorted [][]int) error {
+	for i, s := range sorted {
+		if err := IntValidate(s); err != nil {
+			return fmt.Errorf("slice %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Radix sort: these functions are generated by slicesgen -radix when int is an integer type.
+// They sort integers without comparison, so they are faster than IntSort for large slices.
+
+// radixSortMinLengthInt is the minimum length of a slice that IntRadixSort sorts with radix sort.
+// Shorter slices are sorted by IntSort.
+const radixSortMinLengthInt = 256
+
+// IntRadixSort sorts an array of integers in ascendant order with LSD radix sort.
+// It sorts a byte of items in each pass and skips passes that all items have the same byte,
+// so small values are sorted in fewer passes. It allocates a buffer as large as the array.
+func IntRadixSort(a []int) (err error) {
+	if len(a) < radixSortMinLengthInt {
+		return IntSort(a)
+	}
+	var zero int
+	bits := uint(unsafe.Sizeof(zero)) * 8
+	// flip the sign bit of signed integers so that negative values come first in unsigned order
+	var signBit uint64
+	if zero-1 < zero {
+		signBit = 1 << (bits - 1)
+	}
+	src, dst := a, make([]int, len(a))
+	var offsets [256]int
+	for shift := uint(0); shift < bits; shift += 8 {
+		offsets = [256]int{}
+		for _, v := range src {
+			offsets[byte((uint64(v)^signBit)>>shift)]++
+		}
+		if offsets[byte((uint64(src[0])^signBit)>>shift)] == len(src) {
+			continue
+		}
+		sum := 0
+		for i, count := range offsets {
+			offsets[i] = sum
+			sum += count
+		}
+		for _, v := range src {
+			b := byte((uint64(v) ^ signBit) >> shift)
+			dst[offsets[b]] = v
+			offsets[b]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+	return nil
+}
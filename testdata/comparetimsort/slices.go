@@ -0,0 +1,2262 @@
+// Code generated by slicesgen. DO NOT EDIT.
+
+package comparetimsort
+
+import (
+	"errors"
+	"fmt"
+
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Package timsort provides fast stable sort, uses external comparator.
+//
+// A stable, adaptive, iterative mergesort that requires far fewer than
+// n lg(n) comparisons when running on partially sorted arrays, while
+// offering performance comparable to a traditional mergesort when run
+// on random arrays.  Like all proper mergesorts, this sort is stable and
+// runs O(n log n) time (worst case).  In the worst case, this sort requires
+// temporary storage space for n/2 object references; in the best case,
+// it requires only a small constant amount of space.
+//
+// This implementation was derived from Java's TimSort object by Josh Bloch,
+// which, in turn, was based on the original code by Tim Peters:
+//
+// http://svn.python.org/projects/python/trunk/Objects/listsort.txt
+//
+// Mike K.
+
+// IntCompare is Delegate type that sorting uses as a comparator.
+// It returns a negative number when a < b, a positive number when a > b and zero when a == b.
+type IntCompare func(a, b int) int
+
+// ErrIntComparatorContract is returned when a comparator doesn't satisfy the contract of strict weak ordering
+// (for example, compare(a, a) != 0 or the sort result is not in order). Use errors.Is to check it.
+var ErrIntComparatorContract = errors.New("comparison method violates its general contract")
+
+// ErrIntInternalInvariant is returned when TimSort detects a broken internal invariant in the middle of sort.
+// It is usually caused by a comparator that returns inconsistent results. Use errors.Is to check it.
+var ErrIntInternalInvariant = errors.New("internal invariant of sort is broken")
+
+type timSortHandler struct {
+	a         []int
+	compare   IntCompare
+	minGallop int
+	tmp       []int // Actual runtime type will be Object[], regardless of int
+	stackSize int   // Number of pending runs on stack
+	runBase   []int
+	runLen    []int
+}
+
+// init prepares h to sort a. Buffers of the previous sort are reused if they are large enough.
+func (h *timSortHandler) init(a []int, compare IntCompare) {
+	const initialTmpStorageLength = 256
+	h.a = a
+	h.compare = compare
+	h.minGallop = 7
+	h.stackSize = 0
+
+	len := len(a)
+
+	tmpSize := initialTmpStorageLength
+	if len < 2*tmpSize {
+		tmpSize = len / 2
+	}
+
+	if cap(h.tmp) < tmpSize {
+		h.tmp = make([]int, tmpSize)
+	}
+	stackLen := 40
+	if len < 120 {
+		stackLen = 5
+	} else if len < 1542 {
+		stackLen = 10
+	} else if len < 119151 {
+		stackLen = 19
+	}
+
+	if cap(h.runBase) < stackLen {
+		h.runBase = make([]int, stackLen)
+		h.runLen = make([]int, stackLen)
+	}
+}
+
+// IntSort sorts an array using the provided comparator
+func IntSort(a []int, compare IntCompare) (err error) {
+	var h timSortHandler
+	if err := h.sort(a, compare); err != nil {
+		return err
+	}
+	return checkSortedInt(a, compare)
+}
+
+// sort sorts a with TimSort. It keeps buffers in h for the next sort.
+func (h *timSortHandler) sort(a []int, compare IntCompare) (err error) {
+	const minMerge = 32
+	lo := 0
+	hi := len(a)
+	nRemaining := hi
+	if nRemaining < 2 {
+		return
+	}
+	if nRemaining < minMerge {
+		initRunLen, err := countRunAndMakeAscending(a, lo, hi, compare)
+		if err != nil {
+			return err
+		}
+		return binarySort(a, lo, hi, lo+initRunLen, compare)
+	}
+	h.init(a, compare)
+	minRun, err := minRunLength(nRemaining)
+	if err != nil {
+		return
+	}
+	for {
+		runLen, err := countRunAndMakeAscending(a, lo, hi, compare)
+		if err != nil {
+			return err
+		}
+		if runLen < minRun {
+			force := minRun
+			if nRemaining <= minRun {
+				force = nRemaining
+			}
+			if err = binarySort(a, lo, lo+force, lo+runLen, compare); err != nil {
+				return err
+			}
+			runLen = force
+		}
+		h.pushRun(lo, runLen)
+		if err = h.mergeCollapse(); err != nil {
+			return err
+		}
+		lo += runLen
+		nRemaining -= runLen
+		if nRemaining == 0 {
+			break
+		}
+	}
+	if lo != hi {
+		return fmt.Errorf("%w: lo must equal hi", ErrIntInternalInvariant)
+	}
+	if err = h.mergeForceCollapse(); err != nil {
+		return
+	}
+	if h.stackSize != 1 {
+		return fmt.Errorf("%w: h.stackSize != 1", ErrIntInternalInvariant)
+	}
+	return
+}
+
+// IntSorter sorts slices with TimSort like IntSort, but keeps its temporary buffers to reuse them
+// in the next sort. It avoids allocation when sorting many slices.
+//
+// A IntSorter is not safe for concurrent use. To share sorters between goroutines, keep them in sync.Pool
+// and call Reset before putting them back.
+type IntSorter struct {
+	h       timSortHandler
+	compare IntCompare
+}
+
+// NewIntSorter returns a IntSorter that uses the provided comparator.
+func NewIntSorter(compare IntCompare) *IntSorter {
+	return &IntSorter{compare: compare}
+}
+
+// Sort sorts an array. It returns the same errors as IntSort.
+func (s *IntSorter) Sort(a []int) error {
+	err := s.h.sort(a, s.compare)
+	s.h.a = nil
+	if err != nil {
+		return err
+	}
+	return checkSortedInt(a, s.compare)
+}
+
+// Reset clears the temporary buffer so that it doesn't keep items alive. It keeps the capacity of the buffer.
+func (s *IntSorter) Reset() {
+	var zero int
+	for i := range s.h.tmp {
+		s.h.tmp[i] = zero
+	}
+	s.h.a = nil
+}
+
+// parallelSortMinLengthInt is the minimum length of a chunk that IntParallelSort sorts or merges
+// in its own goroutine. Shorter slices are sorted by IntSort.
+const parallelSortMinLengthInt = 1 << 12
+
+// IntParallelSort sorts an array using the provided comparator with workers goroutines.
+// If workers is zero or negative, it uses runtime.GOMAXPROCS(0).
+//
+// It splits the array into chunks, sorts each chunk by IntSort concurrently and merges them in parallel.
+// It is stable like IntSort and returns the same errors, checking the order after the last merge.
+// It allocates a buffer as large as the array.
+func IntParallelSort(a []int, compare IntCompare, workers int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if maxWorkers := len(a) / parallelSortMinLengthInt; workers > maxWorkers {
+		workers = maxWorkers
+	}
+	if workers < 2 {
+		return IntSort(a, compare)
+	}
+	bounds := make([]int, workers+1)
+	for i := range bounds {
+		bounds[i] = len(a) * i / workers
+	}
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = IntSort(a[bounds[i]:bounds[i+1]], compare)
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	// merge neighbor runs pairwise until one run remains, switching source and destination each round
+	src, dst := a, make([]int, len(a))
+	for runs := len(bounds) - 1; runs > 1; runs = len(bounds) - 1 {
+		parts := workers / (runs / 2)
+		next := make([]int, 0, runs/2+2)
+		for i := 0; i < runs; i += 2 {
+			lo := bounds[i]
+			next = append(next, lo)
+			if i+1 == runs {
+				copy(dst[lo:], src[lo:bounds[i+1]])
+				continue
+			}
+			mid, hi := bounds[i+1], bounds[i+2]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				parallelMergeInt(src[lo:mid], src[mid:hi], dst[lo:hi], compare, parts)
+			}()
+		}
+		wg.Wait()
+		bounds = append(next, len(a))
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+	// each chunk passed the check of IntSort, but a comparator that is inconsistent between chunks
+	// can still leave the merged result out of order
+	return checkSortedInt(a, compare)
+}
+
+// parallelMergeInt merges sorted slices a and b into dst stably. It splits the merge into at most parts goroutines.
+func parallelMergeInt(a, b, dst []int, compare IntCompare, parts int) {
+	if parts < 2 || len(a)+len(b) < 2*parallelSortMinLengthInt {
+		mergeInt(a, b, dst, compare)
+		return
+	}
+	// split the longer slice at the middle. Items of a that are equal to items of b stay in front of them.
+	var i, j int
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		j = IntLowerBound(b, a[i], compare)
+	} else {
+		j = len(b) / 2
+		i = IntUpperBound(a, b[j], compare)
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		parallelMergeInt(a[:i], b[:j], dst[:i+j], compare, parts/2)
+	}()
+	parallelMergeInt(a[i:], b[j:], dst[i+j:], compare, parts-parts/2)
+	wg.Wait()
+}
+
+// mergeInt merges sorted slices a and b into dst stably.
+func mergeInt(a, b, dst []int, compare IntCompare) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if compare(b[j], a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// checkSortedInt returns an error that wraps ErrIntComparatorContract if a is not in order after sort.
+// TimSort doesn't detect all broken comparators by its internal invariants, so IntSort checks the result at last.
+func checkSortedInt(a []int, compare IntCompare) error {
+	for i := 1; i < len(a); i++ {
+		if compare(a[i], a[i-1]) < 0 {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrIntComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// IntValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If IntSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrIntComparatorContract.
+func IntValidate(sorted []int, compare IntCompare) error {
+	for i, item := range sorted {
+		if compare(item, item) != 0 {
+			return fmt.Errorf("%w: item is not equal to itself at %d", ErrIntComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if compare(item, prev) < 0 {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrIntComparatorContract, i-1, i)
+		}
+		if c1, c2 := compare(prev, item), compare(item, prev); (c1 < 0) != (c2 > 0) || (c1 == 0) != (c2 == 0) {
+			return fmt.Errorf("%w: comparison results of items at %d and %d are not antisymmetric", ErrIntComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+func binarySort(a []int, lo, hi, start int, compare IntCompare) (err error) {
+	if lo > start || start > hi {
+		return fmt.Errorf("%w: lo <= start && start <= hi", ErrIntInternalInvariant)
+	}
+
+	if start == lo {
+		start++
+	}
+
+	for ; start < hi; start++ {
+		pivot := a[start]
+		left := lo
+		right := start
+		if left > right {
+			return fmt.Errorf("%w: left <= right", ErrIntInternalInvariant)
+		}
+		for left < right {
+			mid := int(uint(left+right) >> 1)
+			if compare(pivot, a[mid]) < 0 {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+		if left != right {
+			return fmt.Errorf("%w: left == right", ErrIntInternalInvariant)
+		}
+		n := start - left // The number of elements to move
+		if n <= 2 {
+			if n == 2 {
+				a[left+2] = a[left+1]
+			}
+			if n > 0 {
+				a[left+1] = a[left]
+			}
+		} else {
+			copy(a[left+1:], a[left:left+n])
+		}
+		a[left] = pivot
+	}
+	return
+}
+
+func countRunAndMakeAscending(a []int, lo, hi int, compare IntCompare) (int, error) {
+	if lo >= hi {
+		return 0, fmt.Errorf("%w: lo < hi", ErrIntInternalInvariant)
+	}
+	runHi := lo + 1
+	if runHi == hi {
+		return 1, nil
+	}
+	if compare(a[runHi], a[lo]) < 0 {
+		runHi++
+		for runHi < hi && compare(a[runHi], a[runHi-1]) < 0 {
+			runHi++
+		}
+		reverseRange(a, lo, runHi)
+	} else {
+		for runHi < hi && compare(a[runHi], a[runHi-1]) >= 0 {
+			runHi++
+		}
+	}
+	return runHi - lo, nil
+}
+
+func reverseRange(a []int, lo, hi int) {
+	hi--
+	for lo < hi {
+		a[lo], a[hi] = a[hi], a[lo]
+		lo++
+		hi--
+	}
+}
+
+func minRunLength(n int) (int, error) {
+	const minMerge = 32
+	if n < 0 {
+		return 0, fmt.Errorf("%w: n >= 0", ErrIntInternalInvariant)
+	}
+	r := 0 // Becomes 1 if any 1 bits are shifted off
+	for n >= minMerge {
+		r |= (n & 1)
+		n >>= 1
+	}
+	return n + r, nil
+}
+
+func (h *timSortHandler) pushRun(runBase, runLen int) {
+	h.runBase[h.stackSize] = runBase
+	h.runLen[h.stackSize] = runLen
+	h.stackSize++
+}
+
+func (h *timSortHandler) mergeCollapse() (err error) {
+	for h.stackSize > 1 {
+		n := h.stackSize - 2
+		if (n > 0 && h.runLen[n-1] <= h.runLen[n]+h.runLen[n+1]) ||
+			(n > 1 && h.runLen[n-2] <= h.runLen[n-1]+h.runLen[n]) {
+			if h.runLen[n-1] < h.runLen[n+1] {
+				n--
+			}
+			if err = h.mergeAt(n); err != nil {
+				return
+			}
+		} else if h.runLen[n] <= h.runLen[n+1] {
+			if err = h.mergeAt(n); err != nil {
+				return
+			}
+		} else {
+			break
+		}
+	}
+	return
+}
+
+func (h *timSortHandler) mergeForceCollapse() (err error) {
+	for h.stackSize > 1 {
+		n := h.stackSize - 2
+		if n > 0 && h.runLen[n-1] < h.runLen[n+1] {
+			n--
+		}
+		if err = h.mergeAt(n); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (h *timSortHandler) mergeAt(i int) (err error) {
+	if h.stackSize < 2 {
+		return fmt.Errorf("%w: stackSize >= 2", ErrIntInternalInvariant)
+	}
+	if i < 0 {
+		return fmt.Errorf("%w: i >= 0", ErrIntInternalInvariant)
+	}
+	if i != h.stackSize-2 && i != h.stackSize-3 {
+		return fmt.Errorf("%w: if i == stackSize - 2 || i == stackSize - 3", ErrIntInternalInvariant)
+	}
+	base1 := h.runBase[i]
+	len1 := h.runLen[i]
+	base2 := h.runBase[i+1]
+	len2 := h.runLen[i+1]
+	if len1 <= 0 || len2 <= 0 {
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0", ErrIntInternalInvariant)
+	}
+	if base1+len1 != base2 {
+		return fmt.Errorf("%w: base1 + len1 == base2", ErrIntInternalInvariant)
+	}
+	h.runLen[i] = len1 + len2
+	if i == h.stackSize-3 {
+		h.runBase[i+1] = h.runBase[i+2]
+		h.runLen[i+1] = h.runLen[i+2]
+	}
+	h.stackSize--
+	k, err := gallopRight(h.a[base2], h.a, base1, len1, 0, h.compare)
+	if err != nil {
+		return err
+	}
+	if k < 0 {
+		return fmt.Errorf("%w: k >= 0", ErrIntInternalInvariant)
+	}
+	base1 += k
+	len1 -= k
+	if len1 == 0 {
+		return
+	}
+	len2, err = gallopLeft(h.a[base1+len1-1], h.a, base2, len2, len2-1, h.compare)
+	if err != nil {
+		return
+	}
+	if len2 < 0 {
+		return fmt.Errorf("%w: len2 >= 0", ErrIntInternalInvariant)
+	}
+	if len2 == 0 {
+		return
+	}
+	if len1 <= len2 {
+		err = h.mergeLo(base1, len1, base2, len2)
+		if err != nil {
+			return fmt.Errorf("mergeLo: %w", err)
+		}
+	} else {
+		err = h.mergeHi(base1, len1, base2, len2)
+		if err != nil {
+			return fmt.Errorf("mergeHi: %w", err)
+		}
+	}
+	return
+}
+
+func gallopLeft(key int, a []int, base, len, hint int, compare IntCompare) (int, error) {
+	if len <= 0 || hint < 0 || hint >= len {
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrIntInternalInvariant)
+	}
+	lastOfs := 0
+	ofs := 1
+
+	if compare(a[base+hint], key) < 0 {
+		maxOfs := len - hint
+		for ofs < maxOfs && compare(a[base+hint+ofs], key) < 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 {
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs += hint
+		ofs += hint
+	} else {
+		maxOfs := hint + 1
+		for ofs < maxOfs && compare(a[base+hint-ofs], key) >= 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 {
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		tmp := lastOfs
+		lastOfs = hint - ofs
+		ofs = hint - tmp
+	}
+	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrIntInternalInvariant)
+	}
+	lastOfs++
+	for lastOfs < ofs {
+		m := lastOfs + (ofs-lastOfs)/2
+
+		if compare(a[base+m], key) < 0 {
+			lastOfs = m + 1
+		} else {
+			ofs = m
+		}
+	}
+	if lastOfs != ofs {
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrIntInternalInvariant)
+	}
+	return ofs, nil
+}
+
+func gallopRight(key int, a []int, base, len, hint int, compare IntCompare) (int, error) {
+	if len <= 0 || hint < 0 || hint >= len {
+		return 0, fmt.Errorf("%w: len > 0 && hint >= 0 && hint < len", ErrIntInternalInvariant)
+	}
+
+	ofs := 1
+	lastOfs := 0
+	if compare(key, a[base+hint]) < 0 {
+		maxOfs := hint + 1
+		for ofs < maxOfs && compare(key, a[base+hint-ofs]) < 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 { // int overflow
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		tmp := lastOfs
+		lastOfs = hint - ofs
+		ofs = hint - tmp
+	} else {
+		maxOfs := len - hint
+		for ofs < maxOfs && compare(key, a[base+hint+ofs]) >= 0 {
+			lastOfs = ofs
+			ofs = (ofs << 1) + 1
+			if ofs <= 0 {
+				ofs = maxOfs
+			}
+		}
+		if ofs > maxOfs {
+			ofs = maxOfs
+		}
+		lastOfs += hint
+		ofs += hint
+	}
+	if -1 > lastOfs || lastOfs >= ofs || ofs > len {
+		return 0, fmt.Errorf("%w: -1 <= lastOfs && lastOfs < ofs && ofs <= len", ErrIntInternalInvariant)
+	}
+	lastOfs++
+	for lastOfs < ofs {
+		m := lastOfs + (ofs-lastOfs)/2
+
+		if compare(key, a[base+m]) < 0 {
+			ofs = m
+		} else {
+			lastOfs = m + 1
+		}
+	}
+	if lastOfs != ofs {
+		return 0, fmt.Errorf("%w: lastOfs == ofs", ErrIntInternalInvariant)
+	}
+	return ofs, nil
+}
+
+func (h *timSortHandler) mergeLo(base1, len1, base2, len2 int) (err error) {
+	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrIntInternalInvariant)
+	}
+	a := h.a
+	tmp := h.ensureCapacity(len1)
+	copy(tmp, a[base1:base1+len1])
+	cursor1 := 0
+	cursor2 := base2
+	dest := base1
+	a[dest] = a[cursor2]
+	dest++
+	cursor2++
+	len2--
+	if len2 == 0 {
+		copy(a[dest:dest+len1], tmp)
+		return
+	}
+	if len1 == 1 {
+		copy(a[dest:dest+len2], a[cursor2:cursor2+len2])
+		a[dest+len2] = tmp[cursor1]
+		return
+	}
+	compare := h.compare
+	minGallop := h.minGallop
+outer:
+	for {
+		count1 := 0
+		count2 := 0
+		for {
+			if len1 <= 1 || len2 <= 0 {
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrIntInternalInvariant)
+			}
+
+			if compare(a[cursor2], tmp[cursor1]) < 0 {
+				a[dest] = a[cursor2]
+				dest++
+				cursor2++
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 0 {
+					break outer
+				}
+			} else {
+				a[dest] = tmp[cursor1]
+				dest++
+				cursor1++
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 1 {
+					break outer
+				}
+			}
+			if (count1 | count2) >= minGallop {
+				break
+			}
+		}
+		for {
+			if len1 <= 1 || len2 <= 0 {
+				return fmt.Errorf("%w: len1 > 1 && len2 > 0", ErrIntInternalInvariant)
+			}
+			count1, err = gallopRight(a[cursor2], tmp, cursor1, len1, 0, compare)
+			if err != nil {
+				return
+			}
+			if count1 != 0 {
+				copy(a[dest:dest+count1], tmp[cursor1:cursor1+count1])
+				dest += count1
+				cursor1 += count1
+				len1 -= count1
+				if len1 <= 1 {
+					break outer
+				}
+			}
+			a[dest] = a[cursor2]
+			dest++
+			cursor2++
+			len2--
+			if len2 == 0 {
+				break outer
+			}
+			count2, err = gallopLeft(tmp[cursor1], a, cursor2, len2, 0, compare)
+			if err != nil {
+				return
+			}
+			if count2 != 0 {
+				copy(a[dest:dest+count2], a[cursor2:cursor2+count2])
+				dest += count2
+				cursor2 += count2
+				len2 -= count2
+				if len2 == 0 {
+					break outer
+				}
+			}
+			a[dest] = tmp[cursor1]
+			dest++
+			cursor1++
+			len1--
+			if len1 == 1 {
+				break outer
+			}
+			minGallop--
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if minGallop < 0 {
+			minGallop = 0
+		}
+		minGallop += 2
+	}
+	if minGallop < 1 {
+		minGallop = 1
+	}
+	h.minGallop = minGallop
+	if len1 == 1 {
+
+		if len2 <= 0 {
+			return fmt.Errorf("%w: len2 > 0", ErrIntInternalInvariant)
+		}
+		copy(a[dest:dest+len2], a[cursor2:cursor2+len2])
+		a[dest+len2] = tmp[cursor1]
+	} else if len1 == 0 {
+		return ErrIntComparatorContract
+	} else {
+		if len2 != 0 {
+			return fmt.Errorf("%w: len2 == 0", ErrIntInternalInvariant)
+		}
+		if len1 <= 1 {
+			return fmt.Errorf("%w: len1 > 1", ErrIntInternalInvariant)
+		}
+		copy(a[dest:dest+len1], tmp[cursor1:cursor1+len1])
+	}
+	return
+}
+
+func (h *timSortHandler) mergeHi(base1, len1, base2, len2 int) (err error) {
+	if len1 <= 0 || len2 <= 0 || base1+len1 != base2 {
+		return fmt.Errorf("%w: len1 > 0 && len2 > 0 && base1 + len1 == base2", ErrIntInternalInvariant)
+	}
+	a := h.a
+	tmp := h.ensureCapacity(len2)
+	copy(tmp, a[base2:base2+len2])
+	cursor1 := base1 + len1 - 1
+	cursor2 := len2 - 1
+	dest := base2 + len2 - 1
+	a[dest] = a[cursor1]
+	dest--
+	cursor1--
+	len1--
+	if len1 == 0 {
+		dest -= len2 - 1
+		copy(a[dest:dest+len2], tmp)
+		return
+	}
+	if len2 == 1 {
+		dest -= len1 - 1
+		cursor1 -= len1 - 1
+		copy(a[dest:dest+len1], a[cursor1:cursor1+len1])
+		a[dest-1] = tmp[cursor2]
+		return
+	}
+	compare := h.compare
+	minGallop := h.minGallop
+outer:
+	for {
+		count1 := 0 // Number of times in a row that first run won
+		count2 := 0 // Number of times in a row that second run won
+		for {
+			if len1 <= 0 || len2 <= 1 {
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrIntInternalInvariant)
+			}
+			if compare(tmp[cursor2], a[cursor1]) < 0 {
+				a[dest] = a[cursor1]
+				dest--
+				cursor1--
+				count1++
+				count2 = 0
+				len1--
+				if len1 == 0 {
+					break outer
+				}
+			} else {
+				a[dest] = tmp[cursor2]
+				dest--
+				cursor2--
+				count2++
+				count1 = 0
+				len2--
+				if len2 == 1 {
+					break outer
+				}
+			}
+			if (count1 | count2) >= minGallop {
+				break
+			}
+		}
+		for {
+			if len1 <= 0 || len2 <= 1 {
+				return fmt.Errorf("%w: len1 > 0 && len2 > 1", ErrIntInternalInvariant)
+			}
+			if gr, err := gallopRight(tmp[cursor2], a, base1, len1, len1-1, compare); err == nil {
+				count1 = len1 - gr
+			} else {
+				return err
+			}
+			if count1 != 0 {
+				dest -= count1
+				cursor1 -= count1
+				len1 -= count1
+				copy(a[dest+1:dest+1+count1], a[cursor1+1:cursor1+1+count1])
+				if len1 == 0 {
+					break outer
+				}
+			}
+			a[dest] = tmp[cursor2]
+			dest--
+			cursor2--
+			len2--
+			if len2 == 1 {
+				break outer
+			}
+
+			if gl, err := gallopLeft(a[cursor1], tmp, 0, len2, len2-1, compare); err == nil {
+				count2 = len2 - gl
+			} else {
+				return err
+			}
+			if count2 != 0 {
+				dest -= count2
+				cursor2 -= count2
+				len2 -= count2
+				copy(a[dest+1:dest+1+count2], tmp[cursor2+1:cursor2+1+count2])
+				if len2 <= 1 { // len2 == 1 || len2 == 0
+					break outer
+				}
+			}
+			a[dest] = a[cursor1]
+			dest--
+			cursor1--
+			len1--
+			if len1 == 0 {
+				break outer
+			}
+			minGallop--
+
+			if count1 < minGallop && count2 < minGallop {
+				break
+			}
+		}
+		if minGallop < 0 {
+			minGallop = 0
+		}
+		minGallop += 2 // Penalize for leaving gallop mode
+	} // End of "outer" loop
+
+	if minGallop < 1 {
+		minGallop = 1
+	}
+
+	h.minGallop = minGallop // Write back to field
+
+	if len2 == 1 {
+		if len1 <= 0 {
+			return fmt.Errorf("%w: len1 > 0", ErrIntInternalInvariant)
+		}
+		dest -= len1
+		cursor1 -= len1
+
+		copy(a[dest+1:dest+1+len1], a[cursor1+1:cursor1+1+len1])
+		a[dest] = tmp[cursor2] // Move first elt of run2 to front of merge
+	} else if len2 == 0 {
+		return ErrIntComparatorContract
+	} else {
+		if len1 != 0 {
+			return fmt.Errorf("%w: len1 == 0", ErrIntInternalInvariant)
+		}
+
+		if len2 <= 0 {
+			return fmt.Errorf("%w: len2 > 0", ErrIntInternalInvariant)
+		}
+
+		copy(a[dest-(len2-1):dest+1], tmp)
+	}
+	return
+}
+
+func (h *timSortHandler) ensureCapacity(minCapacity int) []int {
+	if len(h.tmp) < minCapacity {
+		// Compute smallest power of 2 > minCapacity
+		newSize := minCapacity
+		newSize |= newSize >> 1
+		newSize |= newSize >> 2
+		newSize |= newSize >> 4
+		newSize |= newSize >> 8
+		newSize |= newSize >> 16
+		newSize++
+
+		if newSize < 0 { // Not bloody likely!
+			newSize = minCapacity
+		} else {
+			ns := len(h.a) / 2
+			if ns < newSize {
+				newSize = ns
+			}
+		}
+
+		h.tmp = make([]int, newSize)
+	}
+
+	return h.tmp
+}
+
+// IntBinarySearch returns first index i that satisfies slices[i] <= item.
+func IntBinarySearch(sorted []int, item int, compare IntCompare) int {
+	// Define f(-1) == false and f(n) == true.
+	// Invariant: f(i-1) == false, f(j) == true.
+	i, j := 0, len(sorted)-1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		// i ≤ h < j
+		if compare(sorted[h], item) < 0 {
+			i = h + 1 // preserves f(i-1) == false
+		} else {
+			j = h // preserves f(j) == true
+		}
+	}
+	// i == j, f(i-1) == false, and f(j) (= f(i)) == true  =>  answer is i.
+	return i
+}
+
+// IntLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func IntLowerBound(sorted []int, item int, compare IntCompare) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if compare(sorted[h], item) < 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// IntUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func IntUpperBound(sorted []int, item int, compare IntCompare) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if compare(sorted[h], item) <= 0 {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopInt returns first index i that satisfies !(sorted[i] < item) like IntLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopInt(sorted []int, item int, compare IntCompare) int {
+	if len(sorted) == 0 || compare(sorted[0], item) >= 0 {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && compare(sorted[hi], item) < 0 {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + IntLowerBound(sorted[lo+1:hi], item, compare)
+}
+
+// IntEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func IntEqualRange(sorted []int, item int, compare IntCompare) (lo, hi int) {
+	lo = IntLowerBound(sorted, item, compare)
+	hi = lo + IntUpperBound(sorted[lo:], item, compare)
+	return lo, hi
+}
+
+// IntCount returns the number of items that are equal to item.
+func IntCount(sorted []int, item int, compare IntCompare) int {
+	lo, hi := IntEqualRange(sorted, item, compare)
+	return hi - lo
+}
+
+// IntFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func IntFloor(sorted []int, item int, compare IntCompare) (int, bool) {
+	i := IntUpperBound(sorted, item, compare)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// IntCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func IntCeiling(sorted []int, item int, compare IntCompare) (int, bool) {
+	i := IntLowerBound(sorted, item, compare)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// IntLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func IntLower(sorted []int, item int, compare IntCompare) (int, bool) {
+	i := IntLowerBound(sorted, item, compare)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// IntHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func IntHigher(sorted []int, item int, compare IntCompare) (int, bool) {
+	i := IntUpperBound(sorted, item, compare)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// IntRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func IntRangeIndexes(sorted []int, from, to int, inclusive bool, compare IntCompare) (lo, hi int) {
+	lo = IntLowerBound(sorted, from, compare)
+	if inclusive {
+		hi = lo + IntUpperBound(sorted[lo:], to, compare)
+	} else {
+		hi = lo + IntLowerBound(sorted[lo:], to, compare)
+	}
+	return lo, hi
+}
+
+// IntRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func IntRange(sorted []int, from, to int, compare IntCompare) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false, compare)
+	return sorted[lo:hi:hi]
+}
+
+// IntRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like IntRange.
+func IntRangeInclusive(sorted []int, from, to int, compare IntCompare) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true, compare)
+	return sorted[lo:hi:hi]
+}
+
+// IntRemoveRange removes items in [from, to) and returns a sorted slice.
+func IntRemoveRange(sorted []int, from, to int, compare IntCompare) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false, compare)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// IntRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func IntRemoveRangeInclusive(sorted []int, from, to int, compare IntCompare) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true, compare)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// IntCountRange returns the number of items in [from, to).
+func IntCountRange(sorted []int, from, to int, compare IntCompare) int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false, compare)
+	return hi - lo
+}
+
+// IntCountRangeInclusive returns the number of items in [from, to].
+func IntCountRangeInclusive(sorted []int, from, to int, compare IntCompare) int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true, compare)
+	return hi - lo
+}
+
+// IntIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
+func IntIndexOf(sorted []int, item int, compare IntCompare) int {
+	if len(sorted) == 0 {
+		return -1
+	}
+	i := IntBinarySearch(sorted, item, compare)
+	if compare(sorted[i], item) == 0 {
+		return i
+	}
+	return -1
+}
+
+// IntContains returns true if item is in a sorted slice. Otherwise false.
+func IntContains(sorted []int, item int, compare IntCompare) bool {
+	if len(sorted) == 0 {
+		return false
+	}
+	i := IntBinarySearch(sorted, item, compare)
+	return compare(sorted[i], item) == 0
+}
+
+// IntInsert inserts item in correct position and returns a sorted slice.
+func IntInsert(sorted []int, item int, compare IntCompare) []int {
+	i := IntBinarySearch(sorted, item, compare)
+	if i == len(sorted)-1 && compare(sorted[i], item) < 0 {
+		return append(sorted, item)
+	}
+	return append(sorted[:i], append([]int{item}, sorted[i:]...)...)
+}
+
+// IntInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling IntInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func IntInsertAll(sorted []int, items []int, compare IntCompare) []int {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]int, len(items))
+	copy(batch, items)
+	IntSort(batch, compare)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && compare(batch[j], sorted[i]) < 0 {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]int, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if compare(batch[j], sorted[i]) < 0 {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// IntInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func IntInsertUnique(sorted []int, item int, compare IntCompare) ([]int, bool) {
+	i := IntLowerBound(sorted, item, compare)
+	if i < len(sorted) && compare(sorted[i], item) == 0 {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]int{item}, sorted[i:]...)...), true
+}
+
+// IntRemove removes item in a sorted slice.
+func IntRemove(sorted []int, item int, compare IntCompare) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	i := IntBinarySearch(sorted, item, compare)
+	if compare(sorted[i], item) == 0 {
+		return IntRemoveAt(sorted, i)
+	}
+	return sorted
+}
+
+// IntRemoveAt removes item in a slice.
+func IntRemoveAt(sorted []int, i int) []int {
+	return append(sorted[:i], sorted[i+1:]...)
+}
+
+// IntRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func IntRemoveAll(sorted []int, items []int, compare IntCompare) []int {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && compare(items[j], item) < 0 {
+			j++
+		}
+		if j < len(items) && compare(items[j], item) == 0 {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// IntRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func IntRemoveIf(sorted []int, pred func(item int) bool) []int {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IntRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func IntRemoveIndexes(sorted []int, indexes []int) []int {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// IntUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func IntUnique(sorted []int, compare IntCompare) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if compare(result[len(result)-1], item) < 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IntUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func IntUniqueFunc(sorted []int, equal func(a, b int) bool) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdInt is the number of source slices that IntIterateOver and IntUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdInt = 8
+
+// IntIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
+func IntIterateOver(compare IntCompare, callback func(item int, srcIndex int), sorted ...[]int) {
+	sourceSlices := make([][]int, 0, len(sorted))
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
+		if len(src) > 0 {
+			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
+		}
+	}
+	if len(sourceSlices) > heapMergeThresholdInt {
+		iterateOverHeapInt(compare, callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearInt(compare, callback, sourceSlices, sliceIndex)
+	}
+}
+
+// iterateOverLinearInt finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapInt when there are a few source slices.
+func iterateOverLinearInt(compare IntCompare, callback func(item int, srcIndex int), sourceSlices [][]int, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
+	}
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < len(sourceSlices); i++ {
+			if compare(sourceSlices[i][indexes[i]], minItem) < 0 {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		callback(minItem, sliceIndex[minSlice])
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapInt keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearInt takes O(N k).
+func iterateOverHeapInt(compare IntCompare, callback func(item int, srcIndex int), sourceSlices [][]int, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if c := compare(x, y); c != 0 {
+			return c < 0
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
+		}
+		down(0)
+	}
+}
+
+// IntUnion unions sorted slices and returns new slices.
+func IntUnion(compare IntCompare, sorted ...[]int) []int {
+	length := 0
+	sourceSlices := make([][]int, 0, len(sorted))
+	for _, src := range sorted {
+		if len(src) > 0 {
+			length += len(src)
+			sourceSlices = append(sourceSlices, src)
+		}
+	}
+	if length == 0 {
+		return nil
+	} else if len(sourceSlices) == 1 {
+		return sourceSlices[0]
+	}
+	if len(sourceSlices) > heapMergeThresholdInt {
+		result := make([]int, 0, length)
+		IntIterateOver(compare, func(item int, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
+	result := make([]int, length)
+	sourceSliceCount := len(sourceSlices)
+	indexes := make([]int, sourceSliceCount)
+	index := 0
+	for {
+		minSlice := 0
+		minItem := sourceSlices[0][indexes[0]]
+		for i := 1; i < sourceSliceCount; i++ {
+			if compare(sourceSlices[i][indexes[i]], minItem) < 0 {
+				minSlice = i
+				minItem = sourceSlices[i][indexes[i]]
+			}
+		}
+		result[index] = minItem
+		index++
+		indexes[minSlice]++
+		if indexes[minSlice] == len(sourceSlices[minSlice]) {
+			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
+			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
+			sourceSliceCount--
+			if len(sourceSlices) == 1 {
+				copy(result[index:], sourceSlices[0][indexes[0]:])
+				return result
+			}
+		}
+	}
+}
+
+// IntUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func IntUnionDistinct(compare IntCompare, sorted ...[]int) []int {
+	var result []int
+	IntIterateOver(compare, func(item int, srcIndex int) {
+		if len(result) == 0 || compare(result[len(result)-1], item) < 0 {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// IntDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
+func IntDifference(compare IntCompare, sorted1, sorted2 []int) []int {
+	var result []int
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopInt(sorted1[i:], sorted2[j], compare)
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopInt(sorted2[j:], sorted1[i], compare)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if compare(sorted1[i], sorted2[j]) == 0 {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// IntSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func IntSymmetricDifference(compare IntCompare, sorted1, sorted2 []int) []int {
+	var result []int
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		c := compare(sorted1[i], sorted2[j])
+		switch {
+		case c < 0:
+			result = append(result, sorted1[i])
+			i++
+		case c > 0:
+			result = append(result, sorted2[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
+	return result
+}
+
+// IntDifferenceAll creates difference group of base and all subtract slices and returns.
+func IntDifferenceAll(compare IntCompare, base []int, subtract ...[]int) []int {
+	return IntDifference(compare, base, IntUnion(compare, subtract...))
+}
+
+// IntDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func IntDiff(compare IntCompare, old, new []int) (added, removed, common []int) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		c := compare(old[i], new[j])
+		switch {
+		case c < 0:
+			removed = append(removed, old[i])
+			i++
+		case c > 0:
+			added = append(added, new[j])
+			j++
+		default:
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// IntIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func IntIsSubset(compare IntCompare, sub, super []int) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopInt(super[j:], item, compare)
+		if j == len(super) || compare(item, super[j]) < 0 {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// IntIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func IntIsSuperset(compare IntCompare, super, sub []int) bool {
+	return IntIsSubset(compare, sub, super)
+}
+
+// IntIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func IntIsDisjoint(compare IntCompare, sorted1, sorted2 []int) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopInt(sorted1[i:], sorted2[j], compare)
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopInt(sorted2[j:], sorted1[i], compare)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if compare(sorted1[i], sorted2[j]) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func IntEqual(compare IntCompare, sorted1, sorted2 []int) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if compare(sorted1[i], sorted2[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntIntersectionSize returns the number of items that IntIntersection returns for sorted1 and sorted2
+// without allocating memory. Like IntIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func IntIntersectionSize(compare IntCompare, sorted1, sorted2 []int) int {
+	// IntIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopInt(long[j:], item, compare)
+		if j == len(long) {
+			break
+		}
+		if compare(item, long[j]) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// IntIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
+func IntIntersection(compare IntCompare, sorted ...[]int) []int {
+	return IntIntersectionFunc(compare, nil, sorted...)
+}
+
+// IntIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func IntIntersectionFunc(compare IntCompare, callback func(item int, indexes []int), sorted ...[]int) []int {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
+	})
+	if len(sorted) > heapMergeThresholdInt {
+		return intersectionHeapInt(compare, callback, sorted, order[0])
+	}
+	return intersectionLinearInt(compare, callback, sorted, order)
+}
+
+// intersectionLinearInt checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapInt when there are a few source slices or the items are spread evenly.
+func intersectionLinearInt(compare IntCompare, callback func(item int, indexes []int), sorted [][]int, order []int) []int {
+	var result []int
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopInt(src[cursors[i]:], value, compare)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if compare(value, src[cursors[i]]) < 0 {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapInt keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearInt when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapInt(compare IntCompare, callback func(item int, indexes []int), sorted [][]int, shortest int) []int {
+	var result []int
+	if len(sorted[shortest]) == 0 {
+		return result
+	}
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if compare(maxItem, sorted[i][0]) < 0 {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return compare(sorted[a][cursors[a]], sorted[b][cursors[b]]) < 0
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if compare(sorted[src][cursors[src]], maxItem) < 0 {
+			cursors[src] += gallopInt(sorted[src][cursors[src]:], maxItem, compare)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; compare(maxItem, item) < 0 {
+				maxItem = item
+			}
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && compare(maxItem, s[cursors[shortest]]) >= 0 {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
+			}
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
+				break
+			}
+		}
+	}
+}
+
+// IntSortedSet is a set that keeps unique items in a sorted slice.
+type IntSortedSet struct {
+	items   []int
+	compare IntCompare
+}
+
+// NewIntSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewIntSortedSet(compare IntCompare, items ...int) *IntSortedSet {
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	IntSort(sorted, compare)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || compare(unique[len(unique)-1], item) != 0 {
+			unique = append(unique, item)
+		}
+	}
+	return &IntSortedSet{items: unique, compare: compare}
+}
+
+// search returns first index i that satisfies item <= s.items[i] and the result of comparison of them.
+// If there is no such item, it returns s.Len() and -1.
+func (s *IntSortedSet) search(item int) (int, int) {
+	i, j := 0, len(s.items)
+	c := -1
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if hc := s.compare(item, s.items[h]); hc > 0 {
+			i = h + 1
+		} else {
+			j = h
+			c = hc
+		}
+	}
+	return i, c
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *IntSortedSet) Add(item int) bool {
+	i, c := s.search(item)
+	if c == 0 {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *IntSortedSet) Delete(item int) bool {
+	i, c := s.search(item)
+	if c != 0 {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *IntSortedSet) Has(item int) bool {
+	_, c := s.search(item)
+	return c == 0
+}
+
+// Len returns the number of items in the set.
+func (s *IntSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *IntSortedSet) At(i int) int {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *IntSortedSet) Range(callback func(i int, item int) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *IntSortedSet) Slice() []int {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *IntSortedSet) Union(other *IntSortedSet) *IntSortedSet {
+	result := make([]int, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, s.items[i])
+			i++
+		} else if c > 0 {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &IntSortedSet{items: result, compare: s.compare}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *IntSortedSet) Intersect(other *IntSortedSet) *IntSortedSet {
+	var result []int
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &IntSortedSet{items: result, compare: s.compare}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *IntSortedSet) Subtract(other *IntSortedSet) *IntSortedSet {
+	var result []int
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		c := s.compare(s.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, s.items[i])
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &IntSortedSet{items: result, compare: s.compare}
+}
+
+// IntSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type IntSortedMultiset struct {
+	items   []int
+	compare IntCompare
+}
+
+// NewIntSortedMultiset creates a sorted multiset that has items.
+func NewIntSortedMultiset(compare IntCompare, items ...int) *IntSortedMultiset {
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	IntSort(sorted, compare)
+	return &IntSortedMultiset{items: sorted, compare: compare}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *IntSortedMultiset) equalRange(item int) (lo, hi int) {
+	return IntEqualRange(m.items, item, m.compare)
+}
+
+// Add adds item to the multiset.
+func (m *IntSortedMultiset) Add(item int) {
+	m.items = IntInsert(m.items, item, m.compare)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *IntSortedMultiset) RemoveOne(item int) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = IntRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *IntSortedMultiset) RemoveAll(item int) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *IntSortedMultiset) Count(item int) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *IntSortedMultiset) Has(item int) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *IntSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *IntSortedMultiset) Distinct() []int {
+	var result []int
+	for i, item := range m.items {
+		if i == 0 || m.compare(result[len(result)-1], item) != 0 {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *IntSortedMultiset) Range(callback func(item int, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && m.compare(m.items[lo], m.items[hi]) == 0 {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *IntSortedMultiset) Slice() []int {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *IntSortedMultiset) Union(other *IntSortedMultiset) *IntSortedMultiset {
+	result := make([]int, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, m.items[i])
+			i++
+		} else if c > 0 {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &IntSortedMultiset{items: result, compare: m.compare}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *IntSortedMultiset) Intersect(other *IntSortedMultiset) *IntSortedMultiset {
+	var result []int
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &IntSortedMultiset{items: result, compare: m.compare}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *IntSortedMultiset) Subtract(other *IntSortedMultiset) *IntSortedMultiset {
+	var result []int
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		c := m.compare(m.items[i], other.items[j])
+		if c < 0 {
+			result = append(result, m.items[i])
+			i++
+		} else if c > 0 {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &IntSortedMultiset{items: result, compare: m.compare}
+}
+
+// Safe API of the compare-timsort template, appended by slicesgen -safe. Like IntSort, the functions take
+// the three-way comparator and report its contract violations with ErrIntComparatorContract.
+
+// ErrIntEmpty is returned when a sorted slice is empty.
+var ErrIntEmpty = errors.New("slice is empty")
+
+// ErrIntNotFound is returned when an item is not in a sorted slice.
+var ErrIntNotFound = errors.New("item is not found")
+
+// ErrIntOutOfRange is returned when an index is out of range of a slice.
+var ErrIntOutOfRange = errors.New("index is out of range")
+
+// ErrIntInvalidRange is returned when the end of a range is less than its start.
+var ErrIntInvalidRange = errors.New("range is invalid")
+
+// IntSafeBinarySearch returns first index i that satisfies slices[i] <= item.
+// It returns ErrIntEmpty if a sorted slice is empty.
+func IntSafeBinarySearch(sorted []int, item int, compare IntCompare) (int, error) {
+	if len(sorted) == 0 {
+		return -1, ErrIntEmpty
+	}
+	return IntBinarySearch(sorted, item, compare), nil
+}
+
+// IntSafeIndexOf returns index of item. It returns ErrIntNotFound if item is not in a sorted slice.
+func IntSafeIndexOf(sorted []int, item int, compare IntCompare) (int, error) {
+	i := IntIndexOf(sorted, item, compare)
+	if i == -1 {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeRemove removes item in a sorted slice. It returns ErrIntNotFound if item is not in a sorted slice.
+func IntSafeRemove(sorted []int, item int, compare IntCompare) ([]int, error) {
+	i := IntIndexOf(sorted, item, compare)
+	if i == -1 {
+		return sorted, ErrIntNotFound
+	}
+	return IntRemoveAt(sorted, i), nil
+}
+
+// IntSafeRemoveAt removes item at the specified index in a slice.
+// It returns an error that wraps ErrIntOutOfRange if the index is out of range.
+func IntSafeRemoveAt(sorted []int, i int) ([]int, error) {
+	if i < 0 || i >= len(sorted) {
+		return sorted, fmt.Errorf("%w: %d (length %d)", ErrIntOutOfRange, i, len(sorted))
+	}
+	return IntRemoveAt(sorted, i), nil
+}
+
+// IntSafeRemoveIndexes removes items at indexes from a sorted slice.
+// It returns an error that wraps ErrIntOutOfRange without modifying the slice if any index is out of range.
+func IntSafeRemoveIndexes(sorted []int, indexes []int) ([]int, error) {
+	for _, i := range indexes {
+		if i < 0 || i >= len(sorted) {
+			return sorted, fmt.Errorf("%w: %d (length %d)", ErrIntOutOfRange, i, len(sorted))
+		}
+	}
+	return IntRemoveIndexes(sorted, indexes), nil
+}
+
+// IntSafeFloor returns index of the greatest item that is less than or equal to item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeFloor(sorted []int, item int, compare IntCompare) (int, error) {
+	i, ok := IntFloor(sorted, item, compare)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeCeiling returns index of the smallest item that is greater than or equal to item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeCeiling(sorted []int, item int, compare IntCompare) (int, error) {
+	i, ok := IntCeiling(sorted, item, compare)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeLower returns index of the greatest item that is strictly less than item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeLower(sorted []int, item int, compare IntCompare) (int, error) {
+	i, ok := IntLower(sorted, item, compare)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeHigher returns index of the smallest item that is strictly greater than item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeHigher(sorted []int, item int, compare IntCompare) (int, error) {
+	i, ok := IntHigher(sorted, item, compare)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeRange returns items in [from, to) of a sorted slice like IntRange.
+// It returns an error that wraps ErrIntInvalidRange if to is less than from.
+func IntSafeRange(sorted []int, from, to int, compare IntCompare) ([]int, error) {
+	if compare(to, from) < 0 {
+		return nil, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRange(sorted, from, to, compare), nil
+}
+
+// IntSafeRangeInclusive returns items in [from, to] of a sorted slice like IntRangeInclusive.
+// It returns an error that wraps ErrIntInvalidRange if to is less than from.
+func IntSafeRangeInclusive(sorted []int, from, to int, compare IntCompare) ([]int, error) {
+	if compare(to, from) < 0 {
+		return nil, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRangeInclusive(sorted, from, to, compare), nil
+}
+
+// IntSafeRemoveRange removes items in [from, to) like IntRemoveRange.
+// It returns an error that wraps ErrIntInvalidRange without modifying the slice if to is less than from.
+func IntSafeRemoveRange(sorted []int, from, to int, compare IntCompare) ([]int, error) {
+	if compare(to, from) < 0 {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRemoveRange(sorted, from, to, compare), nil
+}
+
+// IntSafeRemoveRangeInclusive removes items in [from, to] like IntRemoveRangeInclusive.
+// It returns an error that wraps ErrIntInvalidRange without modifying the slice if to is less than from.
+func IntSafeRemoveRangeInclusive(sorted []int, from, to int, compare IntCompare) ([]int, error) {
+	if compare(to, from) < 0 {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRemoveRangeInclusive(sorted, from, to, compare), nil
+}
+
+// IntSafeInsert inserts item in a sorted slice like IntInsert. Insert into an unsorted slice
+// breaks the order silently, so it returns an error that wraps ErrIntComparatorContract without modifying the slice.
+func IntSafeInsert(sorted []int, item int, compare IntCompare) ([]int, error) {
+	if err := IntValidate(sorted, compare); err != nil {
+		return sorted, err
+	}
+	return IntInsert(sorted, item, compare), nil
+}
+
+// IntSafeUnion merges sorted slices like IntUnion.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeUnion(compare IntCompare, sorted ...[]int) ([]int, error) {
+	if err := validateSourcesInt(sorted, compare); err != nil {
+		return nil, err
+	}
+	return IntUnion(compare, sorted...), nil
+}
+
+// IntSafeIntersection returns common items of sorted slices like IntIntersection.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeIntersection(compare IntCompare, sorted ...[]int) ([]int, error) {
+	if err := validateSourcesInt(sorted, compare); err != nil {
+		return nil, err
+	}
+	return IntIntersection(compare, sorted...), nil
+}
+
+// IntSafeDifference returns items of sorted1 that are not in sorted2 like IntDifference.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeDifference(compare IntCompare, sorted1, sorted2 []int) ([]int, error) {
+	if err := validateSourcesInt([][]int{sorted1, sorted2}, compare); err != nil {
+		return nil, err
+	}
+	return IntDifference(compare, sorted1, sorted2), nil
+}
+
+// IntSafeSymmetricDifference returns items that are in only one of sorted slices like IntSymmetricDifference.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeSymmetricDifference(compare IntCompare, sorted1, sorted2 []int) ([]int, error) {
+	if err := validateSourcesInt([][]int{sorted1, sorted2}, compare); err != nil {
+		return nil, err
+	}
+	return IntSymmetricDifference(compare, sorted1, sorted2), nil
+}
+
+// validateSourcesInt validates each source slice of the set operations and tells which slice is broken.
+func validateSourcesInt(sorted [][]int, compare IntCompare) error {
+	for i, s := range sorted {
+		if err := IntValidate(s, compare); err != nil {
+			return fmt.Errorf("slice %d: %w", i, err)
+		}
+	}
+	return nil
+}
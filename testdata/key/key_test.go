@@ -0,0 +1,157 @@
+package key
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func keyOf(item Record) int {
+	return item.ID
+}
+
+func records(ids []int) []Record {
+	result := make([]Record, len(ids))
+	for i, id := range ids {
+		result[i] = Record{ID: id}
+	}
+	return result
+}
+
+func isSorted(input []Record) bool {
+	return sort.SliceIsSorted(input, func(i, j int) bool {
+		return input[i].ID < input[j].ID
+	})
+}
+
+func TestSortByKey(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("sort by key returns sorted slice", prop.ForAll(func(input []int) bool {
+		sorted := records(input)
+		RecordSortByKey(sorted, keyOf)
+		return isSorted(sorted)
+	}, numSliceGenerator))
+
+	properties.Property("sort by key returns no error with valid keys", prop.ForAll(func(input []int) bool {
+		return RecordSortByKey(records(input), keyOf) == nil
+	}, numSliceGenerator))
+
+	properties.Property("NaN key is reported after sort", prop.ForAll(func(input []int, index int) bool {
+		points := make([]Point, len(input)+1)
+		for i, item := range input {
+			points[i] = Point{X: float64(item)}
+		}
+		points[len(input)] = Point{X: math.NaN()}
+		points[0], points[index%len(points)] = points[index%len(points)], points[0]
+		return errors.Is(PointSortByKey(points, func(item Point) float64 {
+			return item.X
+		}), ErrPointComparatorContract)
+	}, numSliceGenerator, gen.IntRange(0, 100)))
+
+	properties.TestingRun(t)
+}
+
+func TestSearchKey(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("search key found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		sorted := records(input)
+		RecordSortByKey(sorted, keyOf)
+		i := RecordSearchKey(sorted, value, keyOf)
+		return sorted[i].ID == value
+	}, numSliceGenerator))
+
+	properties.Property("search key returns length of slice if key is bigger than all items", prop.ForAll(func(input []int) bool {
+		sorted := records(input)
+		RecordSortByKey(sorted, keyOf)
+		return RecordSearchKey(sorted, sorted[len(sorted)-1].ID+1, keyOf) == len(sorted)
+	}, gen.SliceOfN(20, gen.IntRange(-1000, 1000))))
+
+	properties.TestingRun(t)
+}
+
+func TestIndexOfKey(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("indexOfKey found items", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		sorted := records(input)
+		RecordSortByKey(sorted, keyOf)
+		i := RecordIndexOfKey(sorted, value, keyOf)
+		item, ok := RecordFindKey(sorted, value, keyOf)
+		return i != -1 && sorted[i].ID == value && ok && item.ID == value && RecordContainsKey(sorted, value, keyOf)
+	}, numSliceGenerator))
+
+	properties.Property("indexOfKey returns -1 if not found", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		sorted := records(input[1:])
+		RecordSortByKey(sorted, keyOf)
+		_, ok := RecordFindKey(sorted, value, keyOf)
+		return RecordIndexOfKey(sorted, value, keyOf) == -1 && !ok && !RecordContainsKey(sorted, value, keyOf)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestInsertByKey(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOf(numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("insert by key keeps slice sorted", prop.ForAll(func(input []int, value int) bool {
+		sorted := records(input)
+		RecordSortByKey(sorted, keyOf)
+		inserted := RecordInsertByKey(sorted, Record{ID: value}, keyOf)
+		return len(inserted) == len(input)+1 && isSorted(inserted) && RecordContainsKey(inserted, value, keyOf)
+	}, numSliceGenerator, numberGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestRemoveKey(t *testing.T) {
+	numberGenerator := gen.Int()
+	numSliceGenerator := gen.SliceOfN(20, numberGenerator)
+
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("remove key removes item of slice", prop.ForAll(func(input []int) bool {
+		value := input[0]
+		sorted := records(input)
+		RecordSortByKey(sorted, keyOf)
+
+		removed := RecordRemoveKey(sorted, value, keyOf)
+
+		return len(removed) == len(input)-1 && !RecordContainsKey(removed, value, keyOf)
+	}, numSliceGenerator))
+
+	properties.TestingRun(t)
+}
+
+func TestEmptySlice(t *testing.T) {
+	if RecordIndexOfKey(nil, 10, keyOf) != -1 {
+		t.Error("indexOfKey should return -1 for empty slice")
+	}
+	if len(RecordRemoveKey(nil, 10, keyOf)) != 0 {
+		t.Error("removeKey should return empty slice")
+	}
+	if len(RecordInsertByKey(nil, Record{ID: 10}, keyOf)) != 1 {
+		t.Error("insertByKey should return slice that has only inserted item")
+	}
+}
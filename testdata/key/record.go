@@ -0,0 +1,12 @@
+package key
+
+// Record is a value type of this test
+type Record struct {
+	ID   int
+	Name string
+}
+
+// Point is a value type that has a float key
+type Point struct {
+	X float64
+}
@@ -0,0 +1,130 @@
+package sortedmap
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+)
+
+func cmp(a, b string) bool {
+	return a < b
+}
+
+type operation struct {
+	Key    string
+	Value  int
+	Delete bool
+}
+
+func operationGenerator() gopter.Gen {
+	return gopter.CombineGens(gen.OneConstOf("a", "b", "c", "d", "e", "f", "g", "h"), gen.Int(), gen.Bool()).Map(func(values []interface{}) operation {
+		return operation{Key: values[0].(string), Value: values[1].(int), Delete: values[2].(bool)}
+	})
+}
+
+func apply(operations []operation) (*StringIntSortedMap, map[string]int) {
+	m := NewStringIntSortedMap(cmp)
+	expected := make(map[string]int)
+	for _, op := range operations {
+		if op.Delete {
+			_, ok := expected[op.Key]
+			if m.Delete(op.Key) != ok {
+				return nil, nil
+			}
+			delete(expected, op.Key)
+		} else {
+			m.Put(op.Key, op.Value)
+			expected[op.Key] = op.Value
+		}
+	}
+	return m, expected
+}
+
+func sortedKeys(expected map[string]int) []string {
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSortedMap(t *testing.T) {
+	properties := gopter.NewProperties(nil)
+
+	properties.Property("sorted map works like map", prop.ForAll(func(operations []operation) bool {
+		m, expected := apply(operations)
+		if m == nil || m.Len() != len(expected) {
+			return false
+		}
+		for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			value, ok := m.Get(key)
+			expectedValue, expectedOk := expected[key]
+			if ok != expectedOk || value != expectedValue || m.Has(key) != expectedOk {
+				return false
+			}
+		}
+		keys := sortedKeys(expected)
+		i := 0
+		result := true
+		m.Each(func(key string, value int) bool {
+			k, v := m.At(i)
+			result = result && key == keys[i] && value == expected[key] && k == key && v == value
+			i++
+			return true
+		})
+		return result && i == len(keys)
+	}, gen.SliceOf(operationGenerator())))
+
+	properties.Property("floor and ceiling", prop.ForAll(func(operations []operation, key string) bool {
+		m, expected := apply(operations)
+		keys := sortedKeys(expected)
+		floorKey, floorValue, floorOk := m.Floor(key)
+		ceilingKey, ceilingValue, ceilingOk := m.Ceiling(key)
+		var expectedFloor, expectedCeiling string
+		var expectedFloorOk, expectedCeilingOk bool
+		for _, k := range keys {
+			if k <= key {
+				expectedFloor, expectedFloorOk = k, true
+			}
+			if k >= key && !expectedCeilingOk {
+				expectedCeiling, expectedCeilingOk = k, true
+			}
+		}
+		if floorOk != expectedFloorOk || ceilingOk != expectedCeilingOk {
+			return false
+		}
+		if floorOk && (floorKey != expectedFloor || floorValue != expected[expectedFloor]) {
+			return false
+		}
+		return !ceilingOk || (ceilingKey == expectedCeiling && ceilingValue == expected[expectedCeiling])
+	}, gen.SliceOf(operationGenerator()), gen.OneConstOf("0", "a", "c", "cc", "e", "h", "i")))
+
+	properties.Property("range returns keys in [from, to)", prop.ForAll(func(operations []operation, from, to string) bool {
+		m, expected := apply(operations)
+		var expectedKeys, actualKeys []string
+		for _, k := range sortedKeys(expected) {
+			if from <= k && k < to {
+				expectedKeys = append(expectedKeys, k)
+			}
+		}
+		m.Range(from, to, func(key string, value int) bool {
+			actualKeys = append(actualKeys, key)
+			return value == expected[key]
+		})
+		if len(expectedKeys) != len(actualKeys) {
+			return false
+		}
+		for i := range expectedKeys {
+			if expectedKeys[i] != actualKeys[i] {
+				return false
+			}
+		}
+		return true
+	}, gen.SliceOf(operationGenerator()), gen.OneConstOf("a", "b", "c", "d"), gen.OneConstOf("c", "d", "e", "i")))
+
+	properties.TestingRun(t)
+}
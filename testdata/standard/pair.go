@@ -0,0 +1,7 @@
+package small
+
+// Pair is an item that has a sort key and a value to check stability of PairSort.
+type Pair struct {
+	Key   int
+	Value int
+}
@@ -1,19 +1,51 @@
-// This file was automatically generated by genny.
-// Any changes will be lost if this file is regenerated.
-// see https://github.com/cheekybits/genny
+// Code generated by slicesgen. DO NOT EDIT.
 
 package small
 
-import "sort"
+import (
+	"errors"
+	"fmt"
+
+	"sort"
+)
 
 // IntLessThan is Delegate type that sorting uses as a comparator
 type IntLessThan func(a, b int) bool
 
-// IntSort sorts an array using the provided comparator
+// ErrIntComparatorContract is returned when a comparator doesn't satisfy the contract of strict weak ordering
+// (for example, lt(a, a) returns true or the sort result is not in order). Use errors.Is to check it.
+var ErrIntComparatorContract = errors.New("comparison method violates its general contract")
+
+// IntSort sorts an array using the provided comparator.
+// It uses sort.Slice, so it is not stable. slicesgen -stable generates it with sort.SliceStable.
 func IntSort(a []int, lt IntLessThan) (err error) {
 	sort.Slice(a, func(i, j int) bool {
 		return lt(a[i], a[j])
 	})
+	for i := 1; i < len(a); i++ {
+		if lt(a[i], a[i-1]) {
+			return fmt.Errorf("%w: items at %d and %d are not in order after sort", ErrIntComparatorContract, i-1, i)
+		}
+	}
+	return nil
+}
+
+// IntValidate is an optional validation pass of a sorted slice. It checks that items are in ascendant order
+// and the comparator is consistent for each item and its neighbor. If IntSort leaves items out of order,
+// the comparator violates the contract. It returns an error that wraps ErrIntComparatorContract.
+func IntValidate(sorted []int, lt IntLessThan) error {
+	for i, item := range sorted {
+		if lt(item, item) {
+			return fmt.Errorf("%w: item is less than itself at %d", ErrIntComparatorContract, i)
+		}
+		if i == 0 {
+			continue
+		}
+		prev := sorted[i-1]
+		if lt(item, prev) {
+			return fmt.Errorf("%w: items at %d and %d are not in order", ErrIntComparatorContract, i-1, i)
+		}
+	}
 	return nil
 }
 
@@ -35,8 +67,164 @@ func IntBinarySearch(sorted []int, item int, lt IntLessThan) int {
 	return i
 }
 
+// IntLowerBound returns first index i that satisfies !(sorted[i] < item).
+// It returns len(sorted) if all items are less than item.
+func IntLowerBound(sorted []int, item int, lt IntLessThan) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if lt(sorted[h], item) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// IntUpperBound returns first index i that satisfies item < sorted[i].
+// It returns len(sorted) if no item is greater than item.
+func IntUpperBound(sorted []int, item int, lt IntLessThan) int {
+	i, j := 0, len(sorted)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if !lt(item, sorted[h]) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// gallopInt returns first index i that satisfies !(sorted[i] < item) like IntLowerBound.
+// It searches exponentially from the head of the slice, so it takes O(log i) time.
+func gallopInt(sorted []int, item int, lt IntLessThan) int {
+	if len(sorted) == 0 || !lt(sorted[0], item) {
+		return 0
+	}
+	// Invariant: sorted[lo] < item
+	lo, hi := 0, 1
+	for hi < len(sorted) && lt(sorted[hi], item) {
+		lo = hi
+		hi = hi*2 + 1
+	}
+	if hi > len(sorted) {
+		hi = len(sorted)
+	}
+	return lo + 1 + IntLowerBound(sorted[lo+1:hi], item, lt)
+}
+
+// IntEqualRange returns range [lo, hi) of items that are equal to item.
+// If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+func IntEqualRange(sorted []int, item int, lt IntLessThan) (lo, hi int) {
+	lo = IntLowerBound(sorted, item, lt)
+	hi = lo + IntUpperBound(sorted[lo:], item, lt)
+	return lo, hi
+}
+
+// IntCount returns the number of items that are equal to item.
+func IntCount(sorted []int, item int, lt IntLessThan) int {
+	lo, hi := IntEqualRange(sorted, item, lt)
+	return hi - lo
+}
+
+// IntFloor returns index of the greatest item that is less than or equal to item.
+// If there is no such item, it returns -1 and false.
+func IntFloor(sorted []int, item int, lt IntLessThan) (int, bool) {
+	i := IntUpperBound(sorted, item, lt)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// IntCeiling returns index of the smallest item that is greater than or equal to item.
+// If there is no such item, it returns -1 and false.
+func IntCeiling(sorted []int, item int, lt IntLessThan) (int, bool) {
+	i := IntLowerBound(sorted, item, lt)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// IntLower returns index of the greatest item that is strictly less than item.
+// If there is no such item, it returns -1 and false.
+func IntLower(sorted []int, item int, lt IntLessThan) (int, bool) {
+	i := IntLowerBound(sorted, item, lt)
+	if i == 0 {
+		return -1, false
+	}
+	return i - 1, true
+}
+
+// IntHigher returns index of the smallest item that is strictly greater than item.
+// If there is no such item, it returns -1 and false.
+func IntHigher(sorted []int, item int, lt IntLessThan) (int, bool) {
+	i := IntUpperBound(sorted, item, lt)
+	if i == len(sorted) {
+		return -1, false
+	}
+	return i, true
+}
+
+// IntRangeIndexes returns index range [lo, hi) of items between from and to.
+// If inclusive is false, it selects items in [from, to). Otherwise it selects items in [from, to].
+// If to is less than from, lo == hi.
+func IntRangeIndexes(sorted []int, from, to int, inclusive bool, lt IntLessThan) (lo, hi int) {
+	lo = IntLowerBound(sorted, from, lt)
+	if inclusive {
+		hi = lo + IntUpperBound(sorted[lo:], to, lt)
+	} else {
+		hi = lo + IntLowerBound(sorted[lo:], to, lt)
+	}
+	return lo, hi
+}
+
+// IntRange returns items in [from, to) of a sorted slice.
+// Returned slice shares the underlying array with the sorted slice, but its capacity is limited
+// so appending to it doesn't overwrite the following items.
+func IntRange(sorted []int, from, to int, lt IntLessThan) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false, lt)
+	return sorted[lo:hi:hi]
+}
+
+// IntRangeInclusive returns items in [from, to] of a sorted slice. It shares the underlying array like IntRange.
+func IntRangeInclusive(sorted []int, from, to int, lt IntLessThan) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true, lt)
+	return sorted[lo:hi:hi]
+}
+
+// IntRemoveRange removes items in [from, to) and returns a sorted slice.
+func IntRemoveRange(sorted []int, from, to int, lt IntLessThan) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false, lt)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// IntRemoveRangeInclusive removes items in [from, to] and returns a sorted slice.
+func IntRemoveRangeInclusive(sorted []int, from, to int, lt IntLessThan) []int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true, lt)
+	return append(sorted[:lo], sorted[hi:]...)
+}
+
+// IntCountRange returns the number of items in [from, to).
+func IntCountRange(sorted []int, from, to int, lt IntLessThan) int {
+	lo, hi := IntRangeIndexes(sorted, from, to, false, lt)
+	return hi - lo
+}
+
+// IntCountRangeInclusive returns the number of items in [from, to].
+func IntCountRangeInclusive(sorted []int, from, to int, lt IntLessThan) int {
+	lo, hi := IntRangeIndexes(sorted, from, to, true, lt)
+	return hi - lo
+}
+
 // IntIndexOf returns index of item. If item is not in a sorted slice, it returns -1.
 func IntIndexOf(sorted []int, item int, lt IntLessThan) int {
+	if len(sorted) == 0 {
+		return -1
+	}
 	i := IntBinarySearch(sorted, item, lt)
 	if !lt(sorted[i], item) && !lt(item, sorted[i]) {
 		return i
@@ -46,6 +234,9 @@ func IntIndexOf(sorted []int, item int, lt IntLessThan) int {
 
 // IntContains returns true if item is in a sorted slice. Otherwise false.
 func IntContains(sorted []int, item int, lt IntLessThan) bool {
+	if len(sorted) == 0 {
+		return false
+	}
 	i := IntBinarySearch(sorted, item, lt)
 	return !lt(sorted[i], item) && !lt(item, sorted[i])
 }
@@ -59,8 +250,63 @@ func IntInsert(sorted []int, item int, lt IntLessThan) []int {
 	return append(sorted[:i], append([]int{item}, sorted[i:]...)...)
 }
 
+// IntInsertAll inserts items in correct positions and returns a sorted slice.
+// It sorts a copy of items and merges it into the sorted slice in a single pass, so it is faster than
+// calling IntInsert for each item. If the sorted slice has enough capacity, it merges in place from the back.
+// Inserted items are placed after existing items that are equal to them.
+func IntInsertAll(sorted []int, items []int, lt IntLessThan) []int {
+	if len(items) == 0 {
+		return sorted
+	}
+	batch := make([]int, len(items))
+	copy(batch, items)
+	IntSort(batch, lt)
+	n := len(sorted)
+	length := n + len(batch)
+	if cap(sorted) >= length {
+		result := sorted[:length]
+		i, j := n-1, len(batch)-1
+		for k := length - 1; j >= 0; k-- {
+			if i >= 0 && lt(batch[j], sorted[i]) {
+				result[k] = sorted[i]
+				i--
+			} else {
+				result[k] = batch[j]
+				j--
+			}
+		}
+		return result
+	}
+	result := make([]int, 0, length)
+	var i, j int
+	for i < n && j < len(batch) {
+		if lt(batch[j], sorted[i]) {
+			result = append(result, batch[j])
+			j++
+		} else {
+			result = append(result, sorted[i])
+			i++
+		}
+	}
+	result = append(result, sorted[i:]...)
+	return append(result, batch[j:]...)
+}
+
+// IntInsertUnique inserts item in correct position if a sorted slice doesn't have an item that is equal to it.
+// It returns a sorted slice and true if item is inserted.
+func IntInsertUnique(sorted []int, item int, lt IntLessThan) ([]int, bool) {
+	i := IntLowerBound(sorted, item, lt)
+	if i < len(sorted) && !lt(item, sorted[i]) {
+		return sorted, false
+	}
+	return append(sorted[:i], append([]int{item}, sorted[i:]...)...), true
+}
+
 // IntRemove removes item in a sorted slice.
 func IntRemove(sorted []int, item int, lt IntLessThan) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
 	i := IntBinarySearch(sorted, item, lt)
 	if !lt(sorted[i], item) && !lt(item, sorted[i]) {
 		return IntRemoveAt(sorted, i)
@@ -73,55 +319,189 @@ func IntRemoveAt(sorted []int, i int) []int {
 	return append(sorted[:i], sorted[i+1:]...)
 }
 
+// IntRemoveAll removes all items that are equal to any of items from a sorted slice and returns it.
+// items should be sorted. It compacts the sorted slice in place in a single pass.
+func IntRemoveAll(sorted []int, items []int, lt IntLessThan) []int {
+	if len(items) == 0 {
+		return sorted
+	}
+	result := sorted[:0]
+	j := 0
+	for _, item := range sorted {
+		for j < len(items) && lt(items[j], item) {
+			j++
+		}
+		if j < len(items) && !lt(item, items[j]) {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// IntRemoveIf removes all items that satisfy pred from a sorted slice and returns it.
+// It compacts the sorted slice in place in a single pass.
+func IntRemoveIf(sorted []int, pred func(item int) bool) []int {
+	result := sorted[:0]
+	for _, item := range sorted {
+		if !pred(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IntRemoveIndexes removes items at indexes from a sorted slice and returns it.
+// indexes can be in any order. Duplicated and out of range indexes are ignored.
+func IntRemoveIndexes(sorted []int, indexes []int) []int {
+	if len(indexes) == 0 {
+		return sorted
+	}
+	removes := make([]int, len(indexes))
+	copy(removes, indexes)
+	sort.Ints(removes)
+	result := sorted[:0]
+	j := 0
+	for i, item := range sorted {
+		for j < len(removes) && removes[j] < i {
+			j++
+		}
+		if j < len(removes) && removes[j] == i {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// IntUnique removes duplicated items from a sorted slice and returns it.
+// It compacts the sorted slice in place and keeps the first one of equal items.
+func IntUnique(sorted []int, lt IntLessThan) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if lt(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IntUniqueFunc removes adjacent items that equal reports as equal and returns the slice.
+// It compacts the slice in place and keeps the first one of equal items.
+func IntUniqueFunc(sorted []int, equal func(a, b int) bool) []int {
+	if len(sorted) == 0 {
+		return sorted
+	}
+	result := sorted[:1]
+	for _, item := range sorted[1:] {
+		if !equal(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// heapMergeThresholdInt is the number of source slices that IntIterateOver and IntUnion
+// switch from linear scan to binary heap to find the minimum item.
+const heapMergeThresholdInt = 8
+
 // IntIterateOver iterates over input sorted slices and calls callback with each items in ascendant order.
+// srcIndex is the index of the source slice in the arguments. Equal items are visited in order of srcIndex.
 func IntIterateOver(lt IntLessThan, callback func(item int, srcIndex int), sorted ...[]int) {
 	sourceSlices := make([][]int, 0, len(sorted))
-	for _, src := range sorted {
+	sliceIndex := make([]int, 0, len(sorted))
+	for i, src := range sorted {
 		if len(src) > 0 {
 			sourceSlices = append(sourceSlices, src)
+			sliceIndex = append(sliceIndex, i)
 		}
 	}
-	sourceSliceCount := len(sourceSlices)
-	if sourceSliceCount == 0 {
-		return
-	} else if sourceSliceCount == 1 {
-		for i, value := range sourceSlices[0] {
-			callback(value, i)
-		}
-		return
+	if len(sourceSlices) > heapMergeThresholdInt {
+		iterateOverHeapInt(lt, callback, sourceSlices, sliceIndex)
+	} else {
+		iterateOverLinearInt(lt, callback, sourceSlices, sliceIndex)
 	}
-	indexes := make([]int, sourceSliceCount)
-	sliceIndex := make([]int, sourceSliceCount)
-	for i := range sourceSlices {
-		sliceIndex[i] = i
+}
+
+// iterateOverLinearInt finds the minimum item by scanning heads of all source slices.
+// It is faster than iterateOverHeapInt when there are a few source slices.
+func iterateOverLinearInt(lt IntLessThan, callback func(item int, srcIndex int), sourceSlices [][]int, sliceIndex []int) {
+	if len(sourceSlices) == 0 {
+		return
 	}
-	index := 0
-	for {
+	indexes := make([]int, len(sourceSlices))
+	for len(sourceSlices) > 1 {
 		minSlice := 0
 		minItem := sourceSlices[0][indexes[0]]
-		for i := 1; i < sourceSliceCount; i++ {
+		for i := 1; i < len(sourceSlices); i++ {
 			if lt(sourceSlices[i][indexes[i]], minItem) {
 				minSlice = i
 				minItem = sourceSlices[i][indexes[i]]
 			}
 		}
 		callback(minItem, sliceIndex[minSlice])
-		index++
 		indexes[minSlice]++
 		if indexes[minSlice] == len(sourceSlices[minSlice]) {
 			sourceSlices = append(sourceSlices[:minSlice], sourceSlices[minSlice+1:]...)
 			indexes = append(indexes[:minSlice], indexes[minSlice+1:]...)
 			sliceIndex = append(sliceIndex[:minSlice], sliceIndex[minSlice+1:]...)
-			sourceSliceCount--
-			if len(sourceSlices) == 1 {
-				slice := sourceSlices[0]
-				for i := indexes[0]; i < len(slice); i++ {
-					callback(slice[i], sliceIndex[0])
-				}
+		}
+	}
+	for _, value := range sourceSlices[0][indexes[0]:] {
+		callback(value, sliceIndex[0])
+	}
+}
+
+// iterateOverHeapInt keeps source slices in a binary heap ordered by their head items.
+// It takes O(N log k) time while iterateOverLinearInt takes O(N k).
+func iterateOverHeapInt(lt IntLessThan, callback func(item int, srcIndex int), sourceSlices [][]int, sliceIndex []int) {
+	indexes := make([]int, len(sourceSlices))
+	heap := make([]int, len(sourceSlices))
+	for i := range heap {
+		heap[i] = i
+	}
+	less := func(a, b int) bool {
+		x, y := sourceSlices[a][indexes[a]], sourceSlices[b][indexes[b]]
+		if lt(x, y) {
+			return true
+		} else if lt(y, x) {
+			return false
+		}
+		// keep stability: source slices are ordered by sliceIndex
+		return a < b
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
 				return
 			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
 		}
 	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for len(heap) > 0 {
+		src := heap[0]
+		callback(sourceSlices[src][indexes[src]], sliceIndex[src])
+		indexes[src]++
+		if indexes[src] == len(sourceSlices[src]) {
+			heap[0] = heap[len(heap)-1]
+			heap = heap[:len(heap)-1]
+		}
+		down(0)
+	}
 }
 
 // IntUnion unions sorted slices and returns new slices.
@@ -139,6 +519,13 @@ func IntUnion(lt IntLessThan, sorted ...[]int) []int {
 	} else if len(sourceSlices) == 1 {
 		return sourceSlices[0]
 	}
+	if len(sourceSlices) > heapMergeThresholdInt {
+		result := make([]int, 0, length)
+		IntIterateOver(lt, func(item int, srcIndex int) {
+			result = append(result, item)
+		}, sourceSlices...)
+		return result
+	}
 	result := make([]int, length)
 	sourceSliceCount := len(sourceSlices)
 	indexes := make([]int, sourceSliceCount)
@@ -167,7 +554,46 @@ func IntUnion(lt IntLessThan, sorted ...[]int) []int {
 	}
 }
 
+// IntUnionDistinct unions sorted slices and returns new slices that has each item only once.
+func IntUnionDistinct(lt IntLessThan, sorted ...[]int) []int {
+	var result []int
+	IntIterateOver(lt, func(item int, srcIndex int) {
+		if len(result) == 0 || lt(result[len(result)-1], item) {
+			result = append(result, item)
+		}
+	}, sorted...)
+	return result
+}
+
+// IntDifference creates difference group of sorted slices and returns.
+// It gallops over both slices, so it takes O(m log(n/m)) time when one slice is much smaller than the other.
 func IntDifference(lt IntLessThan, sorted1, sorted2 []int) []int {
+	var result []int
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		k := i + gallopInt(sorted1[i:], sorted2[j], lt)
+		result = append(result, sorted1[i:k]...)
+		i = k
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopInt(sorted2[j:], sorted1[i], lt)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if !lt(sorted1[i], sorted2[j]) {
+			i++
+			j++
+		}
+	}
+	result = append(result, sorted1[i:]...)
+	return result
+}
+
+// IntSymmetricDifference creates symmetric difference group of sorted slices and returns.
+// It contains items that are in only one of sorted1 and sorted2.
+func IntSymmetricDifference(lt IntLessThan, sorted1, sorted2 []int) []int {
 	var result []int
 	var i, j int
 	for i < len(sorted1) && j < len(sorted2) {
@@ -175,6 +601,7 @@ func IntDifference(lt IntLessThan, sorted1, sorted2 []int) []int {
 			result = append(result, sorted1[i])
 			i++
 		} else if lt(sorted2[j], sorted1[i]) {
+			result = append(result, sorted2[j])
 			j++
 		} else {
 			i++
@@ -182,49 +609,746 @@ func IntDifference(lt IntLessThan, sorted1, sorted2 []int) []int {
 		}
 	}
 	result = append(result, sorted1[i:]...)
+	result = append(result, sorted2[j:]...)
 	return result
 }
 
+// IntDifferenceAll creates difference group of base and all subtract slices and returns.
+func IntDifferenceAll(lt IntLessThan, base []int, subtract ...[]int) []int {
+	return IntDifference(lt, base, IntUnion(lt, subtract...))
+}
+
+// IntDiff compares old and new sorted slices and returns items that are only in new (added),
+// items that are only in old (removed) and items that are in both (common).
+func IntDiff(lt IntLessThan, old, new []int) (added, removed, common []int) {
+	var i, j int
+	for i < len(old) && j < len(new) {
+		if lt(old[i], new[j]) {
+			removed = append(removed, old[i])
+			i++
+		} else if lt(new[j], old[i]) {
+			added = append(added, new[j])
+			j++
+		} else {
+			common = append(common, old[i])
+			i++
+			j++
+		}
+	}
+	removed = append(removed, old[i:]...)
+	added = append(added, new[j:]...)
+	return added, removed, common
+}
+
+// IntIsSubset returns true if all items in sub are in super. Duplicated items are matched one by one.
+// It gallops over super and doesn't allocate memory.
+func IntIsSubset(lt IntLessThan, sub, super []int) bool {
+	if len(sub) > len(super) {
+		return false
+	}
+	j := 0
+	for _, item := range sub {
+		j += gallopInt(super[j:], item, lt)
+		if j == len(super) || lt(item, super[j]) {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// IntIsSuperset returns true if super contains all items in sub. Duplicated items are matched one by one.
+// It doesn't allocate memory.
+func IntIsSuperset(lt IntLessThan, super, sub []int) bool {
+	return IntIsSubset(lt, sub, super)
+}
+
+// IntIsDisjoint returns true if sorted1 and sorted2 have no common items.
+// It gallops over both slices and doesn't allocate memory.
+func IntIsDisjoint(lt IntLessThan, sorted1, sorted2 []int) bool {
+	var i, j int
+	for i < len(sorted1) && j < len(sorted2) {
+		i += gallopInt(sorted1[i:], sorted2[j], lt)
+		if i == len(sorted1) {
+			break
+		}
+		j += gallopInt(sorted2[j:], sorted1[i], lt)
+		if j == len(sorted2) {
+			break
+		}
+		// sorted1[i] <= sorted2[j] here
+		if !lt(sorted1[i], sorted2[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IntEqual returns true if sorted1 and sorted2 have the same items.
+// It doesn't allocate memory.
+func IntEqual(lt IntLessThan, sorted1, sorted2 []int) bool {
+	if len(sorted1) != len(sorted2) {
+		return false
+	}
+	for i := range sorted1 {
+		if lt(sorted1[i], sorted2[i]) || lt(sorted2[i], sorted1[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IntIntersectionSize returns the number of items that IntIntersection returns for sorted1 and sorted2
+// without allocating memory. Like IntIntersection, each duplicated item of the shorter slice is counted
+// if an equal item is in the other slice. It gallops over the longer slice with items of the shorter one.
+func IntIntersectionSize(lt IntLessThan, sorted1, sorted2 []int) int {
+	// IntIntersection keeps items of the first slice if the slices have the same length
+	short, long := sorted1, sorted2
+	if len(long) < len(short) {
+		short, long = long, short
+	}
+	var j, count int
+	for _, item := range short {
+		j += gallopInt(long[j:], item, lt)
+		if j == len(long) {
+			break
+		}
+		if !lt(item, long[j]) {
+			count++
+		}
+	}
+	return count
+}
+
+// IntIntersection creates intersection group of sorted slices and returns.
+// It doesn't reorder the sorted argument. It returns nil if no slices are passed.
 func IntIntersection(lt IntLessThan, sorted ...[]int) []int {
-	sort.Slice(sorted, func(i, j int) bool {
-		return len(sorted[i]) < len(sorted[j])
+	return IntIntersectionFunc(lt, nil, sorted...)
+}
+
+// IntIntersectionFunc creates intersection group of sorted slices and returns.
+// If callback is not nil, it is called with each common item and indexes of the item in each sorted slice
+// (indexes[i] is the position in sorted[i]). Don't modify or keep indexes after callback returns.
+// An item of the shortest slice is common if an equal item is anywhere in every other slice,
+// so duplicated items of the shortest slice are all kept.
+// It gallops over larger slices with items of the smallest one, so it takes O(m log(n/m)) time for each slice.
+func IntIntersectionFunc(lt IntLessThan, callback func(item int, indexes []int), sorted ...[]int) []int {
+	if len(sorted) == 0 {
+		return nil
+	}
+	// order has indexes of sorted slices from the shortest. It keeps the caller's argument as is.
+	order := make([]int, len(sorted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(sorted[order[i]]) < len(sorted[order[j]])
 	})
+	if len(sorted) > heapMergeThresholdInt {
+		return intersectionHeapInt(lt, callback, sorted, order[0])
+	}
+	return intersectionLinearInt(lt, callback, sorted, order)
+}
+
+// intersectionLinearInt checks each item of the shortest slice (sorted[order[0]]) against all other slices.
+// It is faster than intersectionHeapInt when there are a few source slices or the items are spread evenly.
+func intersectionLinearInt(lt IntLessThan, callback func(item int, indexes []int), sorted [][]int, order []int) []int {
 	var result []int
-	if len(sorted[0]) == 0 {
+	cursors := make([]int, len(sorted))
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	for n, value := range sorted[order[0]] {
+		found := true
+		for _, i := range order[1:] {
+			src := sorted[i]
+			cursors[i] += gallopInt(src[cursors[i]:], value, lt)
+			if cursors[i] == len(src) {
+				return result
+			}
+			if lt(value, src[cursors[i]]) {
+				found = false
+				break
+			}
+		}
+		if found {
+			result = append(result, value)
+			if callback != nil {
+				copy(indexes, cursors)
+				indexes[order[0]] = n
+				callback(value, indexes)
+			}
+		}
+	}
+	return result
+}
+
+// intersectionHeapInt keeps heads of the source slices in a binary heap and gallops the slice of the minimum head
+// to the maximum head, so a candidate that is missing in any slice is skipped without checking all slices.
+// It is faster than intersectionLinearInt when there are many source slices with long runs of missing items.
+// It returns the same result: items of sorted[shortest] that are in every other slice.
+func intersectionHeapInt(lt IntLessThan, callback func(item int, indexes []int), sorted [][]int, shortest int) []int {
+	var result []int
+	if len(sorted[shortest]) == 0 {
 		return result
 	}
 	cursors := make([]int, len(sorted))
-	terminate := false
-	for _, value := range sorted[0] {
-		needIncrement := false
-		for i := 1; i < len(sorted); i++ {
-			found := false
-			for j := cursors[i]; j < len(sorted[i]); j++ {
-				valueOfOtherSlice := sorted[i][cursors[i]]
-				if lt(valueOfOtherSlice, value) {
-					cursors[i] = j + 1
-				} else if lt(value, valueOfOtherSlice) {
-					needIncrement = true
-					break
-				} else {
-					found = true
-					break
-				}
-			}
-			if needIncrement {
-				break
+	var indexes []int
+	if callback != nil {
+		indexes = make([]int, len(sorted))
+	}
+	maxItem := sorted[shortest][0]
+	heap := make([]int, len(sorted))
+	for i := range heap {
+		heap[i] = i
+		if len(sorted[i]) == 0 {
+			return result
+		}
+		if lt(maxItem, sorted[i][0]) {
+			maxItem = sorted[i][0]
+		}
+	}
+	less := func(a, b int) bool {
+		return lt(sorted[a][cursors[a]], sorted[b][cursors[b]])
+	}
+	down := func(i int) {
+		for {
+			child := 2*i + 1
+			if child >= len(heap) {
+				return
+			}
+			if right := child + 1; right < len(heap) && less(heap[right], heap[child]) {
+				child = right
+			}
+			if !less(heap[child], heap[i]) {
+				return
+			}
+			heap[i], heap[child] = heap[child], heap[i]
+			i = child
+		}
+	}
+	for i := len(heap)/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for {
+		src := heap[0]
+		if lt(sorted[src][cursors[src]], maxItem) {
+			cursors[src] += gallopInt(sorted[src][cursors[src]:], maxItem, lt)
+			if cursors[src] == len(sorted[src]) {
+				return result
+			}
+			if item := sorted[src][cursors[src]]; lt(maxItem, item) {
+				maxItem = item
+			}
+			down(0)
+			continue
+		}
+		// the minimum head is equal to the maximum head, so all heads are equal
+		s := sorted[shortest]
+		for cursors[shortest] < len(s) && !lt(maxItem, s[cursors[shortest]]) {
+			result = append(result, s[cursors[shortest]])
+			if callback != nil {
+				copy(indexes, cursors)
+				callback(s[cursors[shortest]], indexes)
 			}
-			if !found {
-				terminate = true
+			cursors[shortest]++
+		}
+		if cursors[shortest] == len(s) {
+			return result
+		}
+		maxItem = s[cursors[shortest]]
+		// all other heads are equal, so sifting down the shortest slice from its position keeps the heap valid
+		for i, src := range heap {
+			if src == shortest {
+				down(i)
 				break
 			}
 		}
-		if terminate {
-			break
+	}
+}
+
+// IntSortedSet is a set that keeps unique items in a sorted slice.
+type IntSortedSet struct {
+	items []int
+	lt    IntLessThan
+}
+
+// NewIntSortedSet creates a sorted set that has items. Duplicated items are stored only once.
+func NewIntSortedSet(lt IntLessThan, items ...int) *IntSortedSet {
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	IntSort(sorted, lt)
+	unique := sorted[:0]
+	for i, item := range sorted {
+		if i == 0 || lt(unique[len(unique)-1], item) {
+			unique = append(unique, item)
 		}
-		if !needIncrement {
-			result = append(result, value)
+	}
+	return &IntSortedSet{items: unique, lt: lt}
+}
+
+// search returns first index i that satisfies item <= s.items[i]. If there is no such item, it returns s.Len().
+func (s *IntSortedSet) search(item int) int {
+	i, j := 0, len(s.items)
+	for i < j {
+		h := int(uint(i+j) >> 1) // avoid overflow when computing h
+		if s.lt(s.items[h], item) {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	return i
+}
+
+// Add adds item to the set. It returns false if the set already has the item.
+func (s *IntSortedSet) Add(item int) bool {
+	i := s.search(item)
+	if i < len(s.items) && !s.lt(item, s.items[i]) {
+		return false
+	}
+	s.items = append(s.items, item)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = item
+	return true
+}
+
+// Delete removes item from the set. It returns false if the set doesn't have the item.
+func (s *IntSortedSet) Delete(item int) bool {
+	i := s.search(item)
+	if i == len(s.items) || s.lt(item, s.items[i]) {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Has returns true if the set has item. Otherwise false.
+func (s *IntSortedSet) Has(item int) bool {
+	i := s.search(item)
+	return i < len(s.items) && !s.lt(item, s.items[i])
+}
+
+// Len returns the number of items in the set.
+func (s *IntSortedSet) Len() int {
+	return len(s.items)
+}
+
+// At returns the i-th smallest item in the set.
+func (s *IntSortedSet) At(i int) int {
+	return s.items[i]
+}
+
+// Range calls callback with each items in ascendant order. If callback returns false, Range stops the iteration.
+func (s *IntSortedSet) Range(callback func(i int, item int) bool) {
+	for i, item := range s.items {
+		if !callback(i, item) {
+			return
+		}
+	}
+}
+
+// Slice returns sorted items in the set. The returned slice shares storage with the set, so don't modify it.
+func (s *IntSortedSet) Slice() []int {
+	return s.items
+}
+
+// Union returns a new set that has items in s or other.
+func (s *IntSortedSet) Union(other *IntSortedSet) *IntSortedSet {
+	result := make([]int, 0, len(s.items)+len(other.items))
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.lt(s.items[i], other.items[j]) {
+			result = append(result, s.items[i])
+			i++
+		} else if s.lt(other.items[j], s.items[i]) {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &IntSortedSet{items: result, lt: s.lt}
+}
+
+// Intersect returns a new set that has items in both s and other.
+func (s *IntSortedSet) Intersect(other *IntSortedSet) *IntSortedSet {
+	var result []int
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.lt(s.items[i], other.items[j]) {
+			i++
+		} else if s.lt(other.items[j], s.items[i]) {
+			j++
+		} else {
+			result = append(result, s.items[i])
+			i++
+			j++
+		}
+	}
+	return &IntSortedSet{items: result, lt: s.lt}
+}
+
+// Subtract returns a new set that has items in s but not in other.
+func (s *IntSortedSet) Subtract(other *IntSortedSet) *IntSortedSet {
+	var result []int
+	var i, j int
+	for i < len(s.items) && j < len(other.items) {
+		if s.lt(s.items[i], other.items[j]) {
+			result = append(result, s.items[i])
+			i++
+		} else if s.lt(other.items[j], s.items[i]) {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, s.items[i:]...)
+	return &IntSortedSet{items: result, lt: s.lt}
+}
+
+// IntSortedMultiset is a multiset that keeps items in a sorted slice. It can have the same item several times.
+type IntSortedMultiset struct {
+	items []int
+	lt    IntLessThan
+}
+
+// NewIntSortedMultiset creates a sorted multiset that has items.
+func NewIntSortedMultiset(lt IntLessThan, items ...int) *IntSortedMultiset {
+	sorted := make([]int, len(items))
+	copy(sorted, items)
+	IntSort(sorted, lt)
+	return &IntSortedMultiset{items: sorted, lt: lt}
+}
+
+// equalRange returns index range [lo, hi) of items that are equal to item.
+func (m *IntSortedMultiset) equalRange(item int) (lo, hi int) {
+	return IntEqualRange(m.items, item, m.lt)
+}
+
+// Add adds item to the multiset.
+func (m *IntSortedMultiset) Add(item int) {
+	m.items = IntInsert(m.items, item, m.lt)
+}
+
+// RemoveOne removes one item from the multiset. It returns false if the multiset doesn't have the item.
+func (m *IntSortedMultiset) RemoveOne(item int) bool {
+	lo, hi := m.equalRange(item)
+	if lo == hi {
+		return false
+	}
+	m.items = IntRemoveAt(m.items, lo)
+	return true
+}
+
+// RemoveAll removes all items that are equal to item from the multiset. It returns the number of removed items.
+func (m *IntSortedMultiset) RemoveAll(item int) int {
+	lo, hi := m.equalRange(item)
+	m.items = append(m.items[:lo], m.items[hi:]...)
+	return hi - lo
+}
+
+// Count returns the number of items that are equal to item.
+func (m *IntSortedMultiset) Count(item int) int {
+	lo, hi := m.equalRange(item)
+	return hi - lo
+}
+
+// Has returns true if the multiset has item. Otherwise false.
+func (m *IntSortedMultiset) Has(item int) bool {
+	return m.Count(item) > 0
+}
+
+// Len returns the number of items in the multiset including duplicated items.
+func (m *IntSortedMultiset) Len() int {
+	return len(m.items)
+}
+
+// Distinct returns a new sorted slice that has each item in the multiset only once.
+func (m *IntSortedMultiset) Distinct() []int {
+	var result []int
+	for i, item := range m.items {
+		if i == 0 || m.lt(result[len(result)-1], item) {
+			result = append(result, item)
 		}
 	}
 	return result
 }
+
+// Range calls callback with each distinct items and their counts in ascendant order.
+// If callback returns false, Range stops the iteration.
+func (m *IntSortedMultiset) Range(callback func(item int, count int) bool) {
+	for lo := 0; lo < len(m.items); {
+		hi := lo + 1
+		for hi < len(m.items) && !m.lt(m.items[lo], m.items[hi]) {
+			hi++
+		}
+		if !callback(m.items[lo], hi-lo) {
+			return
+		}
+		lo = hi
+	}
+}
+
+// Slice returns sorted items in the multiset. The returned slice shares storage with the multiset, so don't modify it.
+func (m *IntSortedMultiset) Slice() []int {
+	return m.items
+}
+
+// Union returns a new multiset. The count of each item is the larger count in m and other.
+func (m *IntSortedMultiset) Union(other *IntSortedMultiset) *IntSortedMultiset {
+	result := make([]int, 0, len(m.items)+len(other.items))
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.lt(m.items[i], other.items[j]) {
+			result = append(result, m.items[i])
+			i++
+		} else if m.lt(other.items[j], m.items[i]) {
+			result = append(result, other.items[j])
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	result = append(result, other.items[j:]...)
+	return &IntSortedMultiset{items: result, lt: m.lt}
+}
+
+// Intersect returns a new multiset. The count of each item is the smaller count in m and other.
+func (m *IntSortedMultiset) Intersect(other *IntSortedMultiset) *IntSortedMultiset {
+	var result []int
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.lt(m.items[i], other.items[j]) {
+			i++
+		} else if m.lt(other.items[j], m.items[i]) {
+			j++
+		} else {
+			result = append(result, m.items[i])
+			i++
+			j++
+		}
+	}
+	return &IntSortedMultiset{items: result, lt: m.lt}
+}
+
+// Subtract returns a new multiset. The count of each item is the count in m minus the count in other (at least 0).
+func (m *IntSortedMultiset) Subtract(other *IntSortedMultiset) *IntSortedMultiset {
+	var result []int
+	var i, j int
+	for i < len(m.items) && j < len(other.items) {
+		if m.lt(m.items[i], other.items[j]) {
+			result = append(result, m.items[i])
+			i++
+		} else if m.lt(other.items[j], m.items[i]) {
+			j++
+		} else {
+			i++
+			j++
+		}
+	}
+	result = append(result, m.items[i:]...)
+	return &IntSortedMultiset{items: result, lt: m.lt}
+}
+
+// slicesgen -safe appends this file to the standard template. Each function checks the case that makes
+// its counterpart in slices.go panic, return a sentinel value or break the order silently, and returns an error instead.
+
+// ErrIntEmpty is returned when a sorted slice is empty.
+var ErrIntEmpty = errors.New("slice is empty")
+
+// ErrIntNotFound is returned when an item is not in a sorted slice.
+var ErrIntNotFound = errors.New("item is not found")
+
+// ErrIntOutOfRange is returned when an index is out of range of a slice.
+var ErrIntOutOfRange = errors.New("index is out of range")
+
+// ErrIntInvalidRange is returned when the end of a range is less than its start.
+var ErrIntInvalidRange = errors.New("range is invalid")
+
+// IntSafeBinarySearch returns first index i that satisfies slices[i] <= item.
+// It returns ErrIntEmpty if a sorted slice is empty.
+func IntSafeBinarySearch(sorted []int, item int, lt IntLessThan) (int, error) {
+	if len(sorted) == 0 {
+		return -1, ErrIntEmpty
+	}
+	return IntBinarySearch(sorted, item, lt), nil
+}
+
+// IntSafeIndexOf returns index of item. It returns ErrIntNotFound if item is not in a sorted slice.
+func IntSafeIndexOf(sorted []int, item int, lt IntLessThan) (int, error) {
+	i := IntIndexOf(sorted, item, lt)
+	if i == -1 {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeRemove removes item in a sorted slice. It returns ErrIntNotFound if item is not in a sorted slice.
+func IntSafeRemove(sorted []int, item int, lt IntLessThan) ([]int, error) {
+	i := IntIndexOf(sorted, item, lt)
+	if i == -1 {
+		return sorted, ErrIntNotFound
+	}
+	return IntRemoveAt(sorted, i), nil
+}
+
+// IntSafeRemoveAt removes item at the specified index in a slice.
+// It returns an error that wraps ErrIntOutOfRange if the index is out of range.
+func IntSafeRemoveAt(sorted []int, i int) ([]int, error) {
+	if i < 0 || i >= len(sorted) {
+		return sorted, fmt.Errorf("%w: %d (length %d)", ErrIntOutOfRange, i, len(sorted))
+	}
+	return IntRemoveAt(sorted, i), nil
+}
+
+// IntSafeRemoveIndexes removes items at indexes from a sorted slice.
+// It returns an error that wraps ErrIntOutOfRange without modifying the slice if any index is out of range.
+func IntSafeRemoveIndexes(sorted []int, indexes []int) ([]int, error) {
+	for _, i := range indexes {
+		if i < 0 || i >= len(sorted) {
+			return sorted, fmt.Errorf("%w: %d (length %d)", ErrIntOutOfRange, i, len(sorted))
+		}
+	}
+	return IntRemoveIndexes(sorted, indexes), nil
+}
+
+// IntSafeFloor returns index of the greatest item that is less than or equal to item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeFloor(sorted []int, item int, lt IntLessThan) (int, error) {
+	i, ok := IntFloor(sorted, item, lt)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeCeiling returns index of the smallest item that is greater than or equal to item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeCeiling(sorted []int, item int, lt IntLessThan) (int, error) {
+	i, ok := IntCeiling(sorted, item, lt)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeLower returns index of the greatest item that is strictly less than item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeLower(sorted []int, item int, lt IntLessThan) (int, error) {
+	i, ok := IntLower(sorted, item, lt)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeHigher returns index of the smallest item that is strictly greater than item.
+// It returns ErrIntNotFound if there is no such item.
+func IntSafeHigher(sorted []int, item int, lt IntLessThan) (int, error) {
+	i, ok := IntHigher(sorted, item, lt)
+	if !ok {
+		return -1, ErrIntNotFound
+	}
+	return i, nil
+}
+
+// IntSafeRange returns items in [from, to) of a sorted slice like IntRange.
+// It returns an error that wraps ErrIntInvalidRange if to is less than from.
+func IntSafeRange(sorted []int, from, to int, lt IntLessThan) ([]int, error) {
+	if lt(to, from) {
+		return nil, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRange(sorted, from, to, lt), nil
+}
+
+// IntSafeRangeInclusive returns items in [from, to] of a sorted slice like IntRangeInclusive.
+// It returns an error that wraps ErrIntInvalidRange if to is less than from.
+func IntSafeRangeInclusive(sorted []int, from, to int, lt IntLessThan) ([]int, error) {
+	if lt(to, from) {
+		return nil, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRangeInclusive(sorted, from, to, lt), nil
+}
+
+// IntSafeRemoveRange removes items in [from, to) like IntRemoveRange.
+// It returns an error that wraps ErrIntInvalidRange without modifying the slice if to is less than from.
+func IntSafeRemoveRange(sorted []int, from, to int, lt IntLessThan) ([]int, error) {
+	if lt(to, from) {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRemoveRange(sorted, from, to, lt), nil
+}
+
+// IntSafeRemoveRangeInclusive removes items in [from, to] like IntRemoveRangeInclusive.
+// It returns an error that wraps ErrIntInvalidRange without modifying the slice if to is less than from.
+func IntSafeRemoveRangeInclusive(sorted []int, from, to int, lt IntLessThan) ([]int, error) {
+	if lt(to, from) {
+		return sorted, fmt.Errorf("%w: to is less than from", ErrIntInvalidRange)
+	}
+	return IntRemoveRangeInclusive(sorted, from, to, lt), nil
+}
+
+// IntSafeInsert inserts item in a sorted slice like IntInsert. Insert into an unsorted slice
+// breaks the order silently, so it returns an error that wraps ErrIntComparatorContract without modifying the slice.
+func IntSafeInsert(sorted []int, item int, lt IntLessThan) ([]int, error) {
+	if err := IntValidate(sorted, lt); err != nil {
+		return sorted, err
+	}
+	return IntInsert(sorted, item, lt), nil
+}
+
+// IntSafeUnion merges sorted slices like IntUnion.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeUnion(lt IntLessThan, sorted ...[]int) ([]int, error) {
+	if err := validateSourcesInt(sorted, lt); err != nil {
+		return nil, err
+	}
+	return IntUnion(lt, sorted...), nil
+}
+
+// IntSafeIntersection returns common items of sorted slices like IntIntersection.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeIntersection(lt IntLessThan, sorted ...[]int) ([]int, error) {
+	if err := validateSourcesInt(sorted, lt); err != nil {
+		return nil, err
+	}
+	return IntIntersection(lt, sorted...), nil
+}
+
+// IntSafeDifference returns items of sorted1 that are not in sorted2 like IntDifference.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeDifference(lt IntLessThan, sorted1, sorted2 []int) ([]int, error) {
+	if err := validateSourcesInt([][]int{sorted1, sorted2}, lt); err != nil {
+		return nil, err
+	}
+	return IntDifference(lt, sorted1, sorted2), nil
+}
+
+// IntSafeSymmetricDifference returns items that are in only one of sorted slices like IntSymmetricDifference.
+// It returns an error that wraps ErrIntComparatorContract if any slice is not sorted.
+func IntSafeSymmetricDifference(lt IntLessThan, sorted1, sorted2 []int) ([]int, error) {
+	if err := validateSourcesInt([][]int{sorted1, sorted2}, lt); err != nil {
+		return nil, err
+	}
+	return IntSymmetricDifference(lt, sorted1, sorted2), nil
+}
+
+// validateSourcesInt validates each source slice of the set operations and tells which slice is broken.
+func validateSourcesInt(sorted [][]int, lt IntLessThan) error {
+	for i, s := range sorted {
+		if err := IntValidate(s, lt); err != nil {
+			return fmt.Errorf("slice %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ export type GeneratorConfig = {
     sliceType: string;
     acceptLessThan: boolean;
     useTimSort: boolean;
+    safeMode: boolean;
 };
 
 
@@ -3,6 +3,7 @@ import { generate } from './slices-template';
 import { generateComparable } from './slices-comparable-template';
 import { generateTimsort } from './slices-timsort-template';
 import { generateComparableTimsort } from './slices-comparable-timsort-template';
+import { generateSafe, safeImports } from './slices-safe-template';
 
 export { GeneratorConfig } from './config';
 
@@ -21,9 +22,18 @@ export function generateSourceCode(config: GeneratorConfig): string {
             source = generateComparable(config);
         }
     }
+    if (config.safeMode) {
+        source = addImports(source, safeImports) + generateSafe(config);
+    }
     return simpleGoFormat(source);
 }
 
+function addImports(src: string, imports: string[]): string {
+    const missing = imports.filter((name) => src.indexOf(`        "${name}"\n`) === -1);
+    const lines = missing.map((name) => `        "${name}"\n`).join('');
+    return src.replace('    import (\n', `    import (\n${lines}`);
+}
+
 function simpleGoFormat(src: string): string {
     const [_, ...lines] = src.split('\n');
 
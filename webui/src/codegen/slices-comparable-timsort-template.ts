@@ -12,6 +12,10 @@ export function generateComparableTimsort(config: GeneratorConfig): string {
     const union = symbol('Union', true, config);
     const intersection = symbol('Intersection', true, config);
     const difference = symbol('Difference', true, config);
+    const lowerBound = symbol('LowerBound', true, config);
+    const upperBound = symbol('UpperBound', true, config);
+    const equalRange = symbol('EqualRange', true, config);
+    const count = symbol('Count', true, config);
 
     const countRunAndMakeAscending = symbol('countRunAndMakeAscending', false, config);
     const reverseRange = symbol('reverseRange', false, config);
@@ -27,6 +31,7 @@ export function generateComparableTimsort(config: GeneratorConfig): string {
     import (
         "errors"
         "fmt"
+        "sort"
     )
 
     // ${binarySearch} returns first index i that satisfies slices[i] <= item.
@@ -47,8 +52,55 @@ export function generateComparableTimsort(config: GeneratorConfig): string {
         return i
     }
 
+    // ${lowerBound} returns first index i that satisfies !(sorted[i] < item).
+    // It returns len(sorted) if all items are less than item.
+    func ${lowerBound}(sorted []${sliceType}, item ${sliceType}) int {
+        i, j := 0, len(sorted)
+        for i < j {
+            h := int(uint(i+j) >> 1) // avoid overflow when computing h
+            if sorted[h] < item {
+                i = h + 1
+            } else {
+                j = h
+            }
+        }
+        return i
+    }
+
+    // ${upperBound} returns first index i that satisfies item < sorted[i].
+    // It returns len(sorted) if no item is greater than item.
+    func ${upperBound}(sorted []${sliceType}, item ${sliceType}) int {
+        i, j := 0, len(sorted)
+        for i < j {
+            h := int(uint(i+j) >> 1) // avoid overflow when computing h
+            if !(item < sorted[h]) {
+                i = h + 1
+            } else {
+                j = h
+            }
+        }
+        return i
+    }
+
+    // ${equalRange} returns range [lo, hi) of items that are equal to item.
+    // If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+    func ${equalRange}(sorted []${sliceType}, item ${sliceType}) (lo, hi int) {
+        lo = ${lowerBound}(sorted, item)
+        hi = lo + ${upperBound}(sorted[lo:], item)
+        return lo, hi
+    }
+
+    // ${count} returns the number of items that are equal to item.
+    func ${count}(sorted []${sliceType}, item ${sliceType}) int {
+        lo, hi := ${equalRange}(sorted, item)
+        return hi - lo
+    }
+
     // ${indexOf} returns index of item. If item is not in a sorted slice, it returns -1.
     func ${indexOf}(sorted []${sliceType}, item ${sliceType}) int {
+        if len(sorted) == 0 {
+            return -1
+        }
         i := ${binarySearch}(sorted, item)
         if sorted[i] == item {
             return i
@@ -58,6 +110,9 @@ export function generateComparableTimsort(config: GeneratorConfig): string {
 
     // ${contains} returns true if item is in a sorted slice. Otherwise false.
     func ${contains}(sorted []${sliceType}, item ${sliceType}) bool {
+        if len(sorted) == 0 {
+            return false
+        }
         i := ${binarySearch}(sorted, item)
         return sorted[i] == item
     }
@@ -73,6 +128,9 @@ export function generateComparableTimsort(config: GeneratorConfig): string {
 
     // ${remove} removes item in a sorted slice.
     func ${remove}(sorted []${sliceType}, item ${sliceType}) []${sliceType} {
+        if len(sorted) == 0 {
+            return sorted
+        }
         i := ${binarySearch}(sorted, item)
         if sorted[i] == item {
             return append(sorted[:i], sorted[i+1:]...)
@@ -0,0 +1,290 @@
+import { GeneratorConfig, symbol } from './config';
+
+export const safeImports = ['errors', 'fmt'];
+
+export function generateSafe(config: GeneratorConfig): string {
+    const { sliceType, acceptLessThan } = config;
+    const binarySearch = symbol('BinarySearch', true, config);
+    const indexOf = symbol('IndexOf', true, config);
+    const lowerBound = symbol('LowerBound', true, config);
+    const upperBound = symbol('UpperBound', true, config);
+    const insert = symbol('Insert', true, config);
+    const union = symbol('Union', true, config);
+    const intersection = symbol('Intersection', true, config);
+    const difference = symbol('Difference', true, config);
+    const safeBinarySearch = symbol('SafeBinarySearch', true, config);
+    const safeIndexOf = symbol('SafeIndexOf', true, config);
+    const safeRemove = symbol('SafeRemove', true, config);
+    const safeRemoveAt = symbol('SafeRemoveAt', true, config);
+    const safeRemoveIndexes = symbol('SafeRemoveIndexes', true, config);
+    const safeFloor = symbol('SafeFloor', true, config);
+    const safeCeiling = symbol('SafeCeiling', true, config);
+    const safeLower = symbol('SafeLower', true, config);
+    const safeHigher = symbol('SafeHigher', true, config);
+    const safeRange = symbol('SafeRange', true, config);
+    const safeRangeInclusive = symbol('SafeRangeInclusive', true, config);
+    const safeRemoveRange = symbol('SafeRemoveRange', true, config);
+    const safeRemoveRangeInclusive = symbol('SafeRemoveRangeInclusive', true, config);
+    const safeInsert = symbol('SafeInsert', true, config);
+    const safeUnion = symbol('SafeUnion', true, config);
+    const safeIntersection = symbol('SafeIntersection', true, config);
+    const safeDifference = symbol('SafeDifference', true, config);
+    const safeSymmetricDifference = symbol('SafeSymmetricDifference', true, config);
+    const validate = symbol('validate', false, config);
+    const validateSources = symbol('validateSources', false, config);
+    const errEmpty = 'Err' + symbol('Empty', true, config);
+    const errNotFound = 'Err' + symbol('NotFound', true, config);
+    const errOutOfRange = 'Err' + symbol('OutOfRange', true, config);
+    const errInvalidRange = 'Err' + symbol('InvalidRange', true, config);
+    const errComparatorContract = 'Err' + symbol('ComparatorContract', true, config);
+    const lessThan = symbol('LessThan', true, config);
+    const ltParam = acceptLessThan ? `, lt ${lessThan}` : '';
+    const ltArg = acceptLessThan ? ', lt' : '';
+    // set operations take the comparator as the first argument
+    const ltFirstParam = acceptLessThan ? `lt ${lessThan}, ` : '';
+    const ltFirstArg = acceptLessThan ? 'lt, ' : '';
+    const less = (a: string, b: string) => acceptLessThan ? `lt(${a}, ${b})` : `${a} < ${b}`;
+    // the comparable templates compare items with <, so NaN is the item that is not equal to itself
+    const broken = acceptLessThan ? 'lt(item, item)' : 'item != item';
+    const brokenMessage = acceptLessThan ? 'item is less than itself' : 'item is not equal to itself';
+
+    return `
+    // ${errEmpty} is returned when a sorted slice is empty.
+    var ${errEmpty} = errors.New("slice is empty")
+
+    // ${errNotFound} is returned when an item is not in a sorted slice.
+    var ${errNotFound} = errors.New("item is not found")
+
+    // ${errOutOfRange} is returned when an index is out of range of a slice.
+    var ${errOutOfRange} = errors.New("index is out of range")
+
+    // ${errInvalidRange} is returned when the end of a range is less than its start.
+    var ${errInvalidRange} = errors.New("range is invalid")
+
+    // ${errComparatorContract} is returned when a slice is not sorted by the comparator.
+    var ${errComparatorContract} = errors.New("comparison method violates its general contract")
+
+    // ${safeBinarySearch} returns first index i that satisfies slices[i] <= item.
+    // It returns ${errEmpty} if a sorted slice is empty.
+    func ${safeBinarySearch}(sorted []${sliceType}, item ${sliceType}${ltParam}) (int, error) {
+        if len(sorted) == 0 {
+            return -1, ${errEmpty}
+        }
+        return ${binarySearch}(sorted, item${ltArg}), nil
+    }
+
+    // ${safeIndexOf} returns index of item. It returns ${errNotFound} if item is not in a sorted slice.
+    func ${safeIndexOf}(sorted []${sliceType}, item ${sliceType}${ltParam}) (int, error) {
+        i := ${indexOf}(sorted, item${ltArg})
+        if i == -1 {
+            return -1, ${errNotFound}
+        }
+        return i, nil
+    }
+
+    // ${safeRemove} removes item in a sorted slice. It returns ${errNotFound} if item is not in a sorted slice.
+    func ${safeRemove}(sorted []${sliceType}, item ${sliceType}${ltParam}) ([]${sliceType}, error) {
+        i := ${indexOf}(sorted, item${ltArg})
+        if i == -1 {
+            return sorted, ${errNotFound}
+        }
+        return append(sorted[:i], sorted[i+1:]...), nil
+    }
+
+    // ${safeRemoveAt} removes item at the specified index in a slice.
+    // It returns an error that wraps ${errOutOfRange} if the index is out of range.
+    func ${safeRemoveAt}(sorted []${sliceType}, i int) ([]${sliceType}, error) {
+        if i < 0 || i >= len(sorted) {
+            return sorted, fmt.Errorf("%w: %d (length %d)", ${errOutOfRange}, i, len(sorted))
+        }
+        return append(sorted[:i], sorted[i+1:]...), nil
+    }
+
+    // ${safeRemoveIndexes} removes items at indexes from a sorted slice.
+    // It returns an error that wraps ${errOutOfRange} without modifying the slice if any index is out of range.
+    func ${safeRemoveIndexes}(sorted []${sliceType}, indexes []int) ([]${sliceType}, error) {
+        removed := make([]bool, len(sorted))
+        for _, i := range indexes {
+            if i < 0 || i >= len(sorted) {
+                return sorted, fmt.Errorf("%w: %d (length %d)", ${errOutOfRange}, i, len(sorted))
+            }
+            removed[i] = true
+        }
+        result := sorted[:0]
+        for i, item := range sorted {
+            if !removed[i] {
+                result = append(result, item)
+            }
+        }
+        return result, nil
+    }
+
+    // ${safeFloor} returns index of the greatest item that is less than or equal to item.
+    // It returns ${errNotFound} if there is no such item.
+    func ${safeFloor}(sorted []${sliceType}, item ${sliceType}${ltParam}) (int, error) {
+        i := ${upperBound}(sorted, item${ltArg})
+        if i == 0 {
+            return -1, ${errNotFound}
+        }
+        return i - 1, nil
+    }
+
+    // ${safeCeiling} returns index of the smallest item that is greater than or equal to item.
+    // It returns ${errNotFound} if there is no such item.
+    func ${safeCeiling}(sorted []${sliceType}, item ${sliceType}${ltParam}) (int, error) {
+        i := ${lowerBound}(sorted, item${ltArg})
+        if i == len(sorted) {
+            return -1, ${errNotFound}
+        }
+        return i, nil
+    }
+
+    // ${safeLower} returns index of the greatest item that is strictly less than item.
+    // It returns ${errNotFound} if there is no such item.
+    func ${safeLower}(sorted []${sliceType}, item ${sliceType}${ltParam}) (int, error) {
+        i := ${lowerBound}(sorted, item${ltArg})
+        if i == 0 {
+            return -1, ${errNotFound}
+        }
+        return i - 1, nil
+    }
+
+    // ${safeHigher} returns index of the smallest item that is strictly greater than item.
+    // It returns ${errNotFound} if there is no such item.
+    func ${safeHigher}(sorted []${sliceType}, item ${sliceType}${ltParam}) (int, error) {
+        i := ${upperBound}(sorted, item${ltArg})
+        if i == len(sorted) {
+            return -1, ${errNotFound}
+        }
+        return i, nil
+    }
+
+    // ${safeRange} returns items in [from, to) of a sorted slice.
+    // It returns an error that wraps ${errInvalidRange} if to is less than from.
+    func ${safeRange}(sorted []${sliceType}, from, to ${sliceType}${ltParam}) ([]${sliceType}, error) {
+        if ${less('to', 'from')} {
+            return nil, fmt.Errorf("%w: to is less than from", ${errInvalidRange})
+        }
+        lo := ${lowerBound}(sorted, from${ltArg})
+        hi := lo + ${lowerBound}(sorted[lo:], to${ltArg})
+        return sorted[lo:hi:hi], nil
+    }
+
+    // ${safeRangeInclusive} returns items in [from, to] of a sorted slice.
+    // It returns an error that wraps ${errInvalidRange} if to is less than from.
+    func ${safeRangeInclusive}(sorted []${sliceType}, from, to ${sliceType}${ltParam}) ([]${sliceType}, error) {
+        if ${less('to', 'from')} {
+            return nil, fmt.Errorf("%w: to is less than from", ${errInvalidRange})
+        }
+        lo := ${lowerBound}(sorted, from${ltArg})
+        hi := lo + ${upperBound}(sorted[lo:], to${ltArg})
+        return sorted[lo:hi:hi], nil
+    }
+
+    // ${safeRemoveRange} removes items in [from, to) and returns a sorted slice.
+    // It returns an error that wraps ${errInvalidRange} without modifying the slice if to is less than from.
+    func ${safeRemoveRange}(sorted []${sliceType}, from, to ${sliceType}${ltParam}) ([]${sliceType}, error) {
+        if ${less('to', 'from')} {
+            return sorted, fmt.Errorf("%w: to is less than from", ${errInvalidRange})
+        }
+        lo := ${lowerBound}(sorted, from${ltArg})
+        hi := lo + ${lowerBound}(sorted[lo:], to${ltArg})
+        return append(sorted[:lo], sorted[hi:]...), nil
+    }
+
+    // ${safeRemoveRangeInclusive} removes items in [from, to] and returns a sorted slice.
+    // It returns an error that wraps ${errInvalidRange} without modifying the slice if to is less than from.
+    func ${safeRemoveRangeInclusive}(sorted []${sliceType}, from, to ${sliceType}${ltParam}) ([]${sliceType}, error) {
+        if ${less('to', 'from')} {
+            return sorted, fmt.Errorf("%w: to is less than from", ${errInvalidRange})
+        }
+        lo := ${lowerBound}(sorted, from${ltArg})
+        hi := lo + ${upperBound}(sorted[lo:], to${ltArg})
+        return append(sorted[:lo], sorted[hi:]...), nil
+    }
+
+    // ${safeInsert} inserts item in a sorted slice. Insert into an unsorted slice breaks the order silently,
+    // so it returns an error that wraps ${errComparatorContract} without modifying the slice.
+    func ${safeInsert}(sorted []${sliceType}, item ${sliceType}${ltParam}) ([]${sliceType}, error) {
+        if err := ${validate}(sorted${ltArg}); err != nil {
+            return sorted, err
+        }
+        return ${insert}(sorted, item${ltArg}), nil
+    }
+
+    // ${safeUnion} creates union group of sorted slices.
+    // It returns an error that wraps ${errComparatorContract} if any slice is not sorted.
+    func ${safeUnion}(${ltFirstParam}sorted ...[]${sliceType}) ([]${sliceType}, error) {
+        if err := ${validateSources}(sorted${ltArg}); err != nil {
+            return nil, err
+        }
+        return ${union}(${ltFirstArg}sorted...), nil
+    }
+
+    // ${safeIntersection} creates intersection group of sorted slices.
+    // It returns an error that wraps ${errComparatorContract} if any slice is not sorted.
+    func ${safeIntersection}(${ltFirstParam}sorted ...[]${sliceType}) ([]${sliceType}, error) {
+        if err := ${validateSources}(sorted${ltArg}); err != nil {
+            return nil, err
+        }
+        return ${intersection}(${ltFirstArg}sorted...), nil
+    }
+
+    // ${safeDifference} creates difference group of sorted slices.
+    // It returns an error that wraps ${errComparatorContract} if any slice is not sorted.
+    func ${safeDifference}(${ltFirstParam}sorted1, sorted2 []${sliceType}) ([]${sliceType}, error) {
+        if err := ${validateSources}([][]${sliceType}{sorted1, sorted2}${ltArg}); err != nil {
+            return nil, err
+        }
+        return ${difference}(${ltFirstArg}sorted1, sorted2), nil
+    }
+
+    // ${safeSymmetricDifference} returns items that are in only one of sorted slices.
+    // It returns an error that wraps ${errComparatorContract} if any slice is not sorted.
+    func ${safeSymmetricDifference}(${ltFirstParam}sorted1, sorted2 []${sliceType}) ([]${sliceType}, error) {
+        if err := ${validateSources}([][]${sliceType}{sorted1, sorted2}${ltArg}); err != nil {
+            return nil, err
+        }
+        var result []${sliceType}
+        var i, j int
+        for i < len(sorted1) && j < len(sorted2) {
+            if ${less('sorted1[i]', 'sorted2[j]')} {
+                result = append(result, sorted1[i])
+                i++
+            } else if ${less('sorted2[j]', 'sorted1[i]')} {
+                result = append(result, sorted2[j])
+                j++
+            } else {
+                i++
+                j++
+            }
+        }
+        result = append(result, sorted1[i:]...)
+        result = append(result, sorted2[j:]...)
+        return result, nil
+    }
+
+    // ${validate} checks that items are in ascendant order and the comparator is consistent for each item.
+    func ${validate}(sorted []${sliceType}${ltParam}) error {
+        for i, item := range sorted {
+            if ${broken} {
+                return fmt.Errorf("%w: ${brokenMessage} at %d", ${errComparatorContract}, i)
+            }
+            if i > 0 && ${less('item', 'sorted[i-1]')} {
+                return fmt.Errorf("%w: items at %d and %d are not in order", ${errComparatorContract}, i-1, i)
+            }
+        }
+        return nil
+    }
+
+    // ${validateSources} validates each source slice of the set operations and tells which slice is broken.
+    func ${validateSources}(sorted [][]${sliceType}${ltParam}) error {
+        for i, s := range sorted {
+            if err := ${validate}(s${ltArg}); err != nil {
+                return fmt.Errorf("slice %d: %w", i, err)
+            }
+        }
+        return nil
+    }
+`;
+}
@@ -12,6 +12,10 @@ export function generate(config: GeneratorConfig): string {
     const union = symbol('Union', true, config);
     const intersection = symbol('Intersection', true, config);
     const difference = symbol('Difference', true, config);
+    const lowerBound = symbol('LowerBound', true, config);
+    const upperBound = symbol('UpperBound', true, config);
+    const equalRange = symbol('EqualRange', true, config);
+    const count = symbol('Count', true, config);
     const lessThan = symbol('LessThan', true, config);
 
     return `
@@ -50,8 +54,55 @@ export function generate(config: GeneratorConfig): string {
         return i
     }
 
+    // ${lowerBound} returns first index i that satisfies !(sorted[i] < item).
+    // It returns len(sorted) if all items are less than item.
+    func ${lowerBound}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) int {
+        i, j := 0, len(sorted)
+        for i < j {
+            h := int(uint(i+j) >> 1) // avoid overflow when computing h
+            if lt(sorted[h], item) {
+                i = h + 1
+            } else {
+                j = h
+            }
+        }
+        return i
+    }
+
+    // ${upperBound} returns first index i that satisfies item < sorted[i].
+    // It returns len(sorted) if no item is greater than item.
+    func ${upperBound}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) int {
+        i, j := 0, len(sorted)
+        for i < j {
+            h := int(uint(i+j) >> 1) // avoid overflow when computing h
+            if !lt(item, sorted[h]) {
+                i = h + 1
+            } else {
+                j = h
+            }
+        }
+        return i
+    }
+
+    // ${equalRange} returns range [lo, hi) of items that are equal to item.
+    // If item is not in a sorted slice, lo == hi and it is the insertion point of item.
+    func ${equalRange}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) (lo, hi int) {
+        lo = ${lowerBound}(sorted, item, lt)
+        hi = lo + ${upperBound}(sorted[lo:], item, lt)
+        return lo, hi
+    }
+
+    // ${count} returns the number of items that are equal to item.
+    func ${count}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) int {
+        lo, hi := ${equalRange}(sorted, item, lt)
+        return hi - lo
+    }
+
     // ${indexOf} returns index of item. If item is not in a sorted slice, it returns -1.
     func ${indexOf}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) int {
+        if len(sorted) == 0 {
+            return -1
+        }
         i := ${binarySearch}(sorted, item, lt)
         if !lt(sorted[i], item) && !lt(item, sorted[i]) {
             return i
@@ -61,6 +112,9 @@ export function generate(config: GeneratorConfig): string {
 
     // ${contains} returns true if item is in a sorted slice. Otherwise false.
     func ${contains}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) bool {
+        if len(sorted) == 0 {
+            return false
+        }
         i := ${binarySearch}(sorted, item, lt)
         return !lt(sorted[i], item) && !lt(item, sorted[i])
     }
@@ -76,6 +130,9 @@ export function generate(config: GeneratorConfig): string {
 
     // ${remove} removes item in a sorted slice.
     func ${remove}(sorted []${sliceType}, item ${sliceType}, lt ${lessThan}) []${sliceType} {
+        if len(sorted) == 0 {
+            return sorted
+        }
         i := ${binarySearch}(sorted, item, lt)
         if !lt(sorted[i], item) && !lt(item, sorted[i]) {
             return append(sorted[:i], sorted[i+1:]...)